@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/gin-gonic/gin"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+)
+
+// CandleCache is the optional candle-caching side of Store: SQLiteStore
+// implements it so Backtester can reuse previously-fetched CSV history
+// across runs instead of re-reading minute_data.csv every time. RedisStore
+// doesn't implement it, so Backtester falls back straight to CSV for that
+// backend.
+type CandleCache interface {
+	LoadCachedCandles(symbol, interval string) ([]Candle, error)
+	SaveCandles(symbol, interval string, candles []Candle) error
+}
+
+// Backtester runs a registered Strategy over a deterministic CSV replay
+// instead of live exchange data, mirroring bbgo's backtest command: no
+// network calls, the same candle series every run, and maker/taker fees
+// deducted from each trade's ProfitPct before the summary is computed. No
+// slippage is modeled beyond that flat fee drag.
+type Backtester struct {
+	client *binance.Client
+	store  Store
+}
+
+// NewBacktester builds a Backtester sharing the Binance client and store
+// main() already constructs.
+func NewBacktester(client *binance.Client, store Store) *Backtester {
+	return &Backtester{client: client, store: store}
+}
+
+// BacktestSummary extends TradeStats with the metrics that only make sense
+// once an explicit win/loss outcome and holding period are in view.
+type BacktestSummary struct {
+	TradeStats
+	WinRatePct            float64 `json:"win_rate_pct"`
+	AvgHoldingTimeSeconds float64 `json:"avg_holding_time_seconds"`
+}
+
+// BacktestResult is one symbol's replay: its fee-adjusted trades and the
+// summary computed from them.
+type BacktestResult struct {
+	Symbol  string          `json:"symbol"`
+	Trades  []Trade         `json:"trades"`
+	Summary BacktestSummary `json:"summary"`
+}
+
+// BacktestReport is the full response of a Run across every configured
+// symbol, plus a combined equity-curve plot.
+type BacktestReport struct {
+	Results     []BacktestResult `json:"results"`
+	EquityCurve string           `json:"equity_curve_plot,omitempty"`
+}
+
+// Run replays strategyID over every symbol in cfg.Symbols, applying
+// cfg.MakerFeeRate/TakerFeeRate as a round-trip drag on each trade's
+// ProfitPct, and returns a report with one BacktestResult per symbol plus a
+// combined equity curve.
+func (b *Backtester) Run(strategyID string, cfg BacktestConfig, params map[string]interface{}) (*BacktestReport, error) {
+	if len(cfg.Symbols) == 0 {
+		return nil, fmt.Errorf("backtest config has no symbols")
+	}
+	strat, ok := lookupStrategy(strategyID)
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %s", strategyID)
+	}
+	if err := strat.Validate(params); err != nil {
+		return nil, fmt.Errorf("invalid params for %s: %v", strategyID, err)
+	}
+
+	takerFee := cfg.TakerFeeRate
+	if takerFee == 0 {
+		takerFee = 0.001
+	}
+	// One maker fill (entry or exit) and one taker fill per round trip is
+	// the conservative assumption; both in percent, so *100.
+	feeDragPct := (cfg.MakerFeeRate + takerFee) * 100
+
+	user := userFromParams(strategyID, params)
+
+	var results []BacktestResult
+	var allTrades []Trade
+	for _, symbol := range cfg.Symbols {
+		candles, err := b.loadCandles(symbol, user.Interval, cfg)
+		if err != nil || len(candles) == 0 {
+			continue
+		}
+		ts := &TradingStrategy{user: user, client: b.client, candlesOverride: candles}
+		result, err := strat.Execute(context.Background(), ts, symbol, candles, true)
+		if err != nil || result.Error != "" {
+			continue
+		}
+		trades := applyBacktestFees(result.Trades, feeDragPct)
+		summary := summarizeBacktest(trades, candles)
+		results = append(results, BacktestResult{Symbol: symbol, Trades: trades, Summary: summary})
+		allTrades = append(allTrades, trades...)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no candle data available for any configured symbol")
+	}
+
+	curve, err := generateEquityCurvePlot(allTrades)
+	if err != nil {
+		curve = ""
+	}
+	return &BacktestReport{Results: results, EquityCurve: curve}, nil
+}
+
+// loadCandles serves symbol/interval from the store's candle cache when
+// available, falling back to (and then populating) minute_data.csv,
+// trimming the result to cfg's startTime/endTime window.
+func (b *Backtester) loadCandles(symbol, interval string, cfg BacktestConfig) ([]Candle, error) {
+	if interval == "" {
+		interval = "1m"
+	}
+	cache, hasCache := b.store.(CandleCache)
+	if hasCache {
+		if cached, err := cache.LoadCachedCandles(symbol, interval); err == nil && len(cached) > 0 {
+			return filterByWindow(cached, cfg.StartTime, cfg.EndTime), nil
+		}
+	}
+	candles, err := loadCSVData("minute_data.csv")
+	if err != nil {
+		return nil, err
+	}
+	if hasCache {
+		_ = cache.SaveCandles(symbol, interval, candles)
+	}
+	return filterByWindow(candles, cfg.StartTime, cfg.EndTime), nil
+}
+
+// filterByWindow trims candles to [start, end], leaving a bound open when
+// it fails to parse as RFC3339 or is empty.
+func filterByWindow(candles []Candle, start, end string) []Candle {
+	startTime, hasStart := time.Time{}, false
+	if start != "" {
+		if t, err := time.Parse(time.RFC3339, start); err == nil {
+			startTime, hasStart = t, true
+		}
+	}
+	endTime, hasEnd := time.Time{}, false
+	if end != "" {
+		if t, err := time.Parse(time.RFC3339, end); err == nil {
+			endTime, hasEnd = t, true
+		}
+	}
+	if !hasStart && !hasEnd {
+		return candles
+	}
+	var windowed []Candle
+	for _, c := range candles {
+		if hasStart && c.DateTime.Before(startTime) {
+			continue
+		}
+		if hasEnd && c.DateTime.After(endTime) {
+			continue
+		}
+		windowed = append(windowed, c)
+	}
+	return windowed
+}
+
+// applyBacktestFees subtracts feeDragPct (a flat round-trip percentage) from
+// every trade's ProfitPct, returning a copy so the caller's original result
+// slice is left untouched.
+func applyBacktestFees(trades []Trade, feeDragPct float64) []Trade {
+	adjusted := make([]Trade, len(trades))
+	copy(adjusted, trades)
+	for i := range adjusted {
+		adjusted[i].ProfitPct -= feeDragPct
+	}
+	return adjusted
+}
+
+// summarizeBacktest layers win rate and average holding time on top of
+// calculateTradeSummary's existing Sharpe/Sortino/drawdown/profit-factor
+// report.
+func summarizeBacktest(trades []Trade, candles []Candle) BacktestSummary {
+	stats := calculateTradeSummary(trades, candles)
+	summary := BacktestSummary{TradeStats: stats}
+	if stats.TotalTrades == 0 {
+		return summary
+	}
+	summary.WinRatePct = float64(stats.WinningTrades) / float64(stats.TotalTrades) * 100
+
+	var totalHold time.Duration
+	var counted int
+	for _, t := range trades {
+		entryTime, err1 := time.Parse(time.RFC3339, t.EntryTime)
+		exitTime, err2 := time.Parse(time.RFC3339, t.ExitTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		totalHold += exitTime.Sub(entryTime)
+		counted++
+	}
+	if counted > 0 {
+		summary.AvgHoldingTimeSeconds = totalHold.Seconds() / float64(counted)
+	}
+	return summary
+}
+
+// generateEquityCurvePlot renders the cumulative ProfitPct across trades,
+// sorted by exit time, as a base64-encoded PNG line chart.
+func generateEquityCurvePlot(trades []Trade) (string, error) {
+	if len(trades) == 0 {
+		return "", nil
+	}
+	sorted := make([]Trade, len(trades))
+	copy(sorted, trades)
+	sort.Slice(sorted, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, sorted[i].ExitTime)
+		tj, _ := time.Parse(time.RFC3339, sorted[j].ExitTime)
+		return ti.Before(tj)
+	})
+
+	pts := make(plotter.XYs, len(sorted)+1)
+	equity := 0.0
+	pts[0].X, pts[0].Y = 0, 0
+	for i, t := range sorted {
+		equity += t.ProfitPct
+		pts[i+1].X = float64(i + 1)
+		pts[i+1].Y = equity
+	}
+
+	p := plot.New()
+	p.Title.Text = "Backtest Equity Curve"
+	p.X.Label.Text = "Trade #"
+	p.Y.Label.Text = "Cumulative Profit %"
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return "", err
+	}
+	p.Add(line)
+
+	tmpFile, err := os.CreateTemp("", "backtest-equity-*.png")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := p.Save(500, 300, tmpFile.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// registerBacktestEndpoint wires POST /backtest into the given router.
+func registerBacktestEndpoint(router *gin.Engine, backtester *Backtester) {
+	router.POST("/backtest", func(c *gin.Context) {
+		var req struct {
+			Strategy string                 `json:"strategy"`
+			Params   map[string]interface{} `json:"params"`
+			Backtest BacktestConfig         `json:"backtest"`
+		}
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		report, err := backtester.Run(req.Strategy, req.Backtest, req.Params)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, report)
+	})
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestApplyBacktestFees(t *testing.T) {
+	trades := []Trade{{ProfitPct: 2.0}, {ProfitPct: -1.0}}
+	adjusted := applyBacktestFees(trades, 0.2)
+
+	if adjusted[0].ProfitPct != 1.8 {
+		t.Errorf("adjusted[0].ProfitPct = %v, want 1.8", adjusted[0].ProfitPct)
+	}
+	if adjusted[1].ProfitPct != -1.2 {
+		t.Errorf("adjusted[1].ProfitPct = %v, want -1.2", adjusted[1].ProfitPct)
+	}
+	// The original slice must be untouched.
+	if trades[0].ProfitPct != 2.0 || trades[1].ProfitPct != -1.0 {
+		t.Errorf("applyBacktestFees mutated its input: %v", trades)
+	}
+}
+
+func TestSummarizeBacktestWinRateAndHoldingTime(t *testing.T) {
+	trades := []Trade{
+		{ProfitPct: 1.0, EntryTime: "2024-01-01T00:00:00Z", ExitTime: "2024-01-01T01:00:00Z"},
+		{ProfitPct: -1.0, EntryTime: "2024-01-01T00:00:00Z", ExitTime: "2024-01-01T03:00:00Z"},
+	}
+	summary := summarizeBacktest(trades, nil)
+
+	if summary.WinRatePct != 50 {
+		t.Errorf("WinRatePct = %v, want 50", summary.WinRatePct)
+	}
+	if summary.AvgHoldingTimeSeconds != 7200 {
+		t.Errorf("AvgHoldingTimeSeconds = %v, want 7200 (avg of 1h and 3h)", summary.AvgHoldingTimeSeconds)
+	}
+}
+
+func TestSummarizeBacktestNoTrades(t *testing.T) {
+	summary := summarizeBacktest(nil, nil)
+	if summary.TotalTrades != 0 || summary.WinRatePct != 0 || summary.AvgHoldingTimeSeconds != 0 {
+		t.Errorf("summarizeBacktest(nil, nil) = %+v, want all zero", summary)
+	}
+}
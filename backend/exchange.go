@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+// Exchange is the common surface TradingStrategy needs from a venue: enough
+// to fetch historical klines and list tradeable symbols. It deliberately
+// mirrors the narrow slice of goex's GetKlineRecords/GetTicker-style API this
+// module actually uses, rather than every endpoint a real client offers.
+type Exchange interface {
+	Name() string
+	FetchKlines(symbol, interval string, limit int) ([]Candle, error)
+	ExchangeInfo() ([]string, error)
+	// FetchOrderBook returns the current best bid/ask for symbol. Unlike
+	// klines this is a live snapshot, not history, so it's only meaningful
+	// against a real venue (see executeTriangular).
+	FetchOrderBook(symbol string) (bid, ask float64, err error)
+}
+
+// ---------------------- Binance ----------------------
+
+// BinanceExchange adapts the go-binance client to the Exchange interface.
+type BinanceExchange struct {
+	client *binance.Client
+}
+
+// NewBinanceExchange wraps an existing Binance client (e.g. the one main()
+// already constructs for streaming) as an Exchange.
+func NewBinanceExchange(client *binance.Client) *BinanceExchange {
+	return &BinanceExchange{client: client}
+}
+
+func (b *BinanceExchange) Name() string { return "binance" }
+
+func (b *BinanceExchange) FetchKlines(symbol, interval string, limit int) ([]Candle, error) {
+	klines, err := b.client.NewKlinesService().
+		Symbol(symbol).
+		Interval(interval).
+		Limit(limit).
+		Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("binance: fetching klines for %s: %v", symbol, err)
+	}
+	candles := make([]Candle, 0, len(klines))
+	for _, k := range klines {
+		open, _ := strconv.ParseFloat(k.Open, 64)
+		high, _ := strconv.ParseFloat(k.High, 64)
+		low, _ := strconv.ParseFloat(k.Low, 64)
+		closePrice, _ := strconv.ParseFloat(k.Close, 64)
+		volume, _ := strconv.ParseFloat(k.Volume, 64)
+		dt := time.UnixMilli(k.OpenTime).In(time.FixedZone("IST", 5*3600+1800))
+		candles = append(candles, Candle{
+			Timestamp: k.OpenTime, Open: open, High: high, Low: low, Close: closePrice, Volume: volume, DateTime: dt,
+		})
+	}
+	return candles, nil
+}
+
+func (b *BinanceExchange) FetchOrderBook(symbol string) (bid, ask float64, err error) {
+	tickers, err := b.client.NewListBookTickersService().Symbol(symbol).Do(context.Background())
+	if err != nil {
+		return 0, 0, fmt.Errorf("binance: fetching book ticker for %s: %v", symbol, err)
+	}
+	if len(tickers) == 0 {
+		return 0, 0, fmt.Errorf("binance: no book ticker returned for %s", symbol)
+	}
+	bid, _ = strconv.ParseFloat(tickers[0].BidPrice, 64)
+	ask, _ = strconv.ParseFloat(tickers[0].AskPrice, 64)
+	return bid, ask, nil
+}
+
+func (b *BinanceExchange) ExchangeInfo() ([]string, error) {
+	info, err := b.client.NewExchangeInfoService().Do(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("binance: fetching exchange info: %v", err)
+	}
+	symbols := make([]string, 0, len(info.Symbols))
+	for _, sym := range info.Symbols {
+		symbols = append(symbols, sym.Symbol)
+	}
+	return symbols, nil
+}
+
+// ---------------------- MAX ----------------------
+
+// MAXExchange talks to MAX's (max.maicoin.com) public REST API directly,
+// since there is no official Go client in this module's dependency set.
+type MAXExchange struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewMAXExchange builds a MAXExchange pointed at the production API.
+func NewMAXExchange() *MAXExchange {
+	return &MAXExchange{baseURL: "https://max-api.maicoin.com", http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (m *MAXExchange) Name() string { return "max" }
+
+func (m *MAXExchange) FetchKlines(symbol, interval string, limit int) ([]Candle, error) {
+	url := fmt.Sprintf("%s/api/v2/k?market=%s&period=%s&limit=%d", m.baseURL, symbol, maxPeriod(interval), limit)
+	var raw [][]float64
+	if err := getJSON(m.http, url, &raw); err != nil {
+		return nil, fmt.Errorf("max: fetching klines for %s: %v", symbol, err)
+	}
+	candles := make([]Candle, 0, len(raw))
+	for _, row := range raw {
+		if len(row) < 6 {
+			continue
+		}
+		ts := int64(row[0]) * 1000
+		candles = append(candles, Candle{
+			Timestamp: ts, Open: row[1], High: row[2], Low: row[3], Close: row[4], Volume: row[5],
+			DateTime: time.UnixMilli(ts).In(time.FixedZone("IST", 5*3600+1800)),
+		})
+	}
+	return candles, nil
+}
+
+func (m *MAXExchange) ExchangeInfo() ([]string, error) {
+	url := fmt.Sprintf("%s/api/v2/markets", m.baseURL)
+	var markets []struct {
+		ID string `json:"id"`
+	}
+	if err := getJSON(m.http, url, &markets); err != nil {
+		return nil, fmt.Errorf("max: fetching markets: %v", err)
+	}
+	symbols := make([]string, 0, len(markets))
+	for _, mkt := range markets {
+		symbols = append(symbols, mkt.ID)
+	}
+	return symbols, nil
+}
+
+func (m *MAXExchange) FetchOrderBook(symbol string) (bid, ask float64, err error) {
+	url := fmt.Sprintf("%s/api/v2/tickers/%s", m.baseURL, symbol)
+	var ticker struct {
+		Buy  string `json:"buy"`
+		Sell string `json:"sell"`
+	}
+	if err := getJSON(m.http, url, &ticker); err != nil {
+		return 0, 0, fmt.Errorf("max: fetching ticker for %s: %v", symbol, err)
+	}
+	bid, _ = strconv.ParseFloat(ticker.Buy, 64)
+	ask, _ = strconv.ParseFloat(ticker.Sell, 64)
+	return bid, ask, nil
+}
+
+// maxPeriod converts a Binance-style interval ("1m", "1h") into the minute
+// count MAX's kline endpoint expects.
+func maxPeriod(interval string) string {
+	switch interval {
+	case "1h":
+		return "60"
+	case "1d":
+		return "1440"
+	default:
+		return "1"
+	}
+}
+
+// ---------------------- OKX ----------------------
+
+// OKXExchange talks to OKX's public v5 REST API directly.
+type OKXExchange struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewOKXExchange builds an OKXExchange pointed at the production API.
+func NewOKXExchange() *OKXExchange {
+	return &OKXExchange{baseURL: "https://www.okx.com", http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (o *OKXExchange) Name() string { return "okx" }
+
+func (o *OKXExchange) FetchKlines(symbol, interval string, limit int) ([]Candle, error) {
+	url := fmt.Sprintf("%s/api/v5/market/candles?instId=%s&bar=%s&limit=%d", o.baseURL, symbol, interval, limit)
+	var resp struct {
+		Data [][]string `json:"data"`
+	}
+	if err := getJSON(o.http, url, &resp); err != nil {
+		return nil, fmt.Errorf("okx: fetching klines for %s: %v", symbol, err)
+	}
+	candles := make([]Candle, 0, len(resp.Data))
+	// OKX returns candles newest-first; reverse so callers see oldest-first,
+	// matching Binance/MAX and every strategy's chronological assumptions.
+	for i := len(resp.Data) - 1; i >= 0; i-- {
+		row := resp.Data[i]
+		if len(row) < 6 {
+			continue
+		}
+		tsMillis, _ := strconv.ParseInt(row[0], 10, 64)
+		open, _ := strconv.ParseFloat(row[1], 64)
+		high, _ := strconv.ParseFloat(row[2], 64)
+		low, _ := strconv.ParseFloat(row[3], 64)
+		closePrice, _ := strconv.ParseFloat(row[4], 64)
+		volume, _ := strconv.ParseFloat(row[5], 64)
+		candles = append(candles, Candle{
+			Timestamp: tsMillis, Open: open, High: high, Low: low, Close: closePrice, Volume: volume,
+			DateTime: time.UnixMilli(tsMillis).In(time.FixedZone("IST", 5*3600+1800)),
+		})
+	}
+	return candles, nil
+}
+
+func (o *OKXExchange) ExchangeInfo() ([]string, error) {
+	url := fmt.Sprintf("%s/api/v5/public/instruments?instType=SPOT", o.baseURL)
+	var resp struct {
+		Data []struct {
+			InstID string `json:"instId"`
+		} `json:"data"`
+	}
+	if err := getJSON(o.http, url, &resp); err != nil {
+		return nil, fmt.Errorf("okx: fetching instruments: %v", err)
+	}
+	symbols := make([]string, 0, len(resp.Data))
+	for _, inst := range resp.Data {
+		symbols = append(symbols, inst.InstID)
+	}
+	return symbols, nil
+}
+
+func (o *OKXExchange) FetchOrderBook(symbol string) (bid, ask float64, err error) {
+	url := fmt.Sprintf("%s/api/v5/market/ticker?instId=%s", o.baseURL, symbol)
+	var resp struct {
+		Data []struct {
+			BidPx string `json:"bidPx"`
+			AskPx string `json:"askPx"`
+		} `json:"data"`
+	}
+	if err := getJSON(o.http, url, &resp); err != nil {
+		return 0, 0, fmt.Errorf("okx: fetching ticker for %s: %v", symbol, err)
+	}
+	if len(resp.Data) == 0 {
+		return 0, 0, fmt.Errorf("okx: no ticker data for %s", symbol)
+	}
+	bid, _ = strconv.ParseFloat(resp.Data[0].BidPx, 64)
+	ask, _ = strconv.ParseFloat(resp.Data[0].AskPx, 64)
+	return bid, ask, nil
+}
+
+// getJSON is a small shared helper for the REST-backed adapters: GET url and
+// decode the JSON body into out.
+func getJSON(client *http.Client, url string, out interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// ---------------------- CSV / backtest-only ----------------------
+
+// CSVExchange is the backtest/CSV-only driver: it never hits the network and
+// always serves whatever loadCSVData reads from minute_data.csv, ignoring
+// the requested symbol/interval/limit.
+type CSVExchange struct{}
+
+// NewCSVExchange builds a CSVExchange.
+func NewCSVExchange() *CSVExchange { return &CSVExchange{} }
+
+func (c *CSVExchange) Name() string { return "csv" }
+
+func (c *CSVExchange) FetchKlines(symbol, interval string, limit int) ([]Candle, error) {
+	return loadCSVData("minute_data.csv")
+}
+
+func (c *CSVExchange) ExchangeInfo() ([]string, error) {
+	return nil, fmt.Errorf("csv: exchange info is not available for the CSV-only driver")
+}
+
+// FetchOrderBook has no real book to read from a CSV file, so it synthesizes
+// one from the last candle's close with a tight fixed spread. Good enough to
+// exercise executeTriangular against recorded data, not a live order book.
+func (c *CSVExchange) FetchOrderBook(symbol string) (bid, ask float64, err error) {
+	candles, err := loadCSVData("minute_data.csv")
+	if err != nil {
+		return 0, 0, fmt.Errorf("csv: fetching synthetic order book: %v", err)
+	}
+	if len(candles) == 0 {
+		return 0, 0, fmt.Errorf("csv: no data available for synthetic order book")
+	}
+	last := candles[len(candles)-1].Close
+	spread := last * 0.0005
+	return last - spread, last + spread, nil
+}
+
+// ---------------------- Session manager ----------------------
+
+// SessionManager keeps one Exchange adapter per exchange ID, modeled on the
+// `sessions:` block bbgo-style configs use to name a venue once and reuse it
+// across strategies.
+type SessionManager struct {
+	sessions map[string]Exchange
+}
+
+// NewSessionManager registers the Binance, MAX, OKX, and CSV adapters under
+// their Name(). binanceClient is shared with the rest of main() (e.g. the
+// WebSocket streaming endpoint) rather than constructed twice.
+func NewSessionManager(binanceClient *binance.Client) *SessionManager {
+	sm := &SessionManager{sessions: map[string]Exchange{}}
+	for _, ex := range []Exchange{
+		NewBinanceExchange(binanceClient),
+		NewMAXExchange(),
+		NewOKXExchange(),
+		NewCSVExchange(),
+	} {
+		sm.sessions[ex.Name()] = ex
+	}
+	return sm
+}
+
+// Get returns the Exchange registered under name, if any.
+func (sm *SessionManager) Get(name string) (Exchange, bool) {
+	ex, ok := sm.sessions[name]
+	return ex, ok
+}
+
+// Names lists every registered exchange ID.
+func (sm *SessionManager) Names() []string {
+	names := make([]string, 0, len(sm.sessions))
+	for name := range sm.sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
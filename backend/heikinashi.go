@@ -0,0 +1,38 @@
+package main
+
+import "math"
+
+// toHeikinAshi converts a standard candle series to Heikin-Ashi candles,
+// smoothing out noise before the strategies' indicators (stochastic,
+// Bollinger, momentum, local extrema) see the series. Gated behind
+// User.UseHeikinAshi in fetchData so a backtest can compare the standard and
+// HA-smoothed series on the same dataset. Timestamp/DateTime/Volume are
+// carried over unchanged; only Open/High/Low/Close are recomputed.
+func toHeikinAshi(candles []Candle) []Candle {
+	if len(candles) == 0 {
+		return candles
+	}
+	ha := make([]Candle, len(candles))
+	for i, c := range candles {
+		ha[i] = c
+		ha[i].Close = (c.Open + c.High + c.Low + c.Close) / 4
+		if i == 0 {
+			ha[i].Open = (c.Open + c.Close) / 2
+		} else {
+			ha[i].Open = (ha[i-1].Open + ha[i-1].Close) / 2
+		}
+		ha[i].High = math.Max(c.High, math.Max(ha[i].Open, ha[i].Close))
+		ha[i].Low = math.Min(c.Low, math.Min(ha[i].Open, ha[i].Close))
+	}
+	return ha
+}
+
+// candleModeLabel is the plot-title suffix generatePlots/generateHarmonicPlot
+// append so a chart makes it clear whether it was rendered from raw or
+// Heikin-Ashi candles.
+func candleModeLabel(useHeikinAshi bool) string {
+	if useHeikinAshi {
+		return " (Heikin-Ashi)"
+	}
+	return ""
+}
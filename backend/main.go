@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/csv"
+	"flag"
 	"fmt"
+	"image/color"
 	"io"
 	"log"
 	"math"
-	"math/rand"
 	"net/http"
 	"os"
 	"sort"
@@ -44,6 +45,21 @@ type Trade struct {
 	// Optional RSI values for RSI strategies:
 	EntryRSI float64 `json:"entry_rsi,omitempty"`
 	ExitRSI  float64 `json:"exit_rsi,omitempty"`
+	// ExitReason records why the position was closed: "signal" (opposite
+	// entry signal), "stop_loss"/"take_profit" (ATR band, or HARMONIC's
+	// pattern levels), "roi_stop_loss"/"roi_take_profit"/"atr_band"/
+	// "trailing_stop" (shouldExit), "max_hold" (IRR), "end_of_data" (HARMONIC
+	// position closed at the last candle), or "triangular_arbitrage" (TRI).
+	ExitReason      string  `json:"exit_reason,omitempty"`
+	StopLossPrice   float64 `json:"stop_loss_price,omitempty"`
+	TakeProfitPrice float64 `json:"take_profit_price,omitempty"`
+	// MFEPct/MAEPct are the best/worst unrealized PnL (%) seen while the
+	// trade was open, populated by calculateTradeSummary.
+	MFEPct float64 `json:"mfe_pct,omitempty"`
+	MAEPct float64 `json:"mae_pct,omitempty"`
+	// Pattern names the harmonic pattern (e.g. "Shark", "Gartley") that
+	// triggered this trade, populated by executeHarmonic.
+	Pattern string `json:"pattern,omitempty"`
 }
 
 // User holds trading parameters.
@@ -53,8 +69,69 @@ type User struct {
 	BuyThreshold  float64
 	SellThreshold float64
 	TradeType     string // "long" or "short"
-	Strategy      string // "RSI", "MA", "RSI_MA", "KAGE", "KITSUNE", "RYU", "SAKURA", "HIKARI", "TENSHI", "ZEN", "RAMSEY"
+	Strategy      string // "RSI", "MA", "RSI_MA", "KAGE", "KITSUNE", "RYU", "SAKURA", "HIKARI", "TENSHI", "ZEN", "RAMSEY", "IRR", "HARMONIC", "TRI"
 	MAPeriod      int
+
+	// IRR strategy knobs (see executeIRR).
+	Interval  string
+	Window    int
+	FastMA    int
+	SlowMA    int
+	Threshold float64
+
+	// PivotK is the fractal half-width used by executeHarmonic's swing
+	// high/low detector: a bar is a pivot if it is the strict extreme among
+	// PivotK bars on each side.
+	PivotK int
+
+	// Risk-management knobs consumed by RiskManager.ApplyExits. Zero values
+	// fall back to the defaults in TradingStrategy.riskManager.
+	ATRWindow               int
+	StopLossFactor          float64
+	TakeProfitFactor        float64
+	TrailingActivationRatio []float64
+	TrailingCallbackRate    []float64
+
+	// Exits layers additional exit conditions (see shouldExit in risk.go) on
+	// top of the ATR stop/take-profit band above: absolute ROI stop/take
+	// profit and a symmetric ATR-band breach. Its own
+	// TrailingActivationRatio/TrailingCallbackRate are ignored in favor of
+	// the fields above; TradingStrategy.riskManager copies them across.
+	Exits ExitRules
+
+	// Triangular arbitrage knobs consumed by executeTriangular. TriPaths
+	// lists the 3-symbol cycles to scan; MinSpreadRatio and TakerFeeRate
+	// fall back to the defaults in executeTriangular when zero.
+	TriPaths       []TriPath
+	MinSpreadRatio float64
+	TakerFeeRate   float64
+
+	// RAMSEY strategy knobs (see executeRamsey in strategy_ramsey.go).
+	// RamseySymbols is the basket correlated against each other; Window and
+	// Interval size the candle history each leg is resampled from;
+	// CorrelationThreshold/TargetCliqueSize/TargetIndependentSize tune the
+	// Bron-Kerbosch signal; CadenceSeconds, if set, starts a background
+	// roller that recomputes the matrix on that cadence.
+	RamseySymbols               []string
+	RamseyWindow                int
+	RamseyCorrelationThreshold  float64
+	RamseyTargetCliqueSize      int
+	RamseyTargetIndependentSize int
+	RamseyCadenceSeconds        int
+
+	// UseHeikinAshi makes fetchData replace the raw candle series with its
+	// Heikin-Ashi transform (see toHeikinAshi) before any strategy consumes
+	// it, smoothing noise out of the stochastic/Bollinger/momentum/extrema
+	// indicators at the cost of lagging the raw price.
+	UseHeikinAshi bool
+}
+
+// TriPath is one triangular-arbitrage cycle: three symbols sharing a common
+// intermediate asset, e.g. Leg1=BTCUSDT, Leg2=ETHBTC, Leg3=ETHUSDT.
+type TriPath struct {
+	Leg1 string `json:"leg1"`
+	Leg2 string `json:"leg2"`
+	Leg3 string `json:"leg3"`
 }
 
 // ---------------------- Utility Functions ----------------------
@@ -137,35 +214,12 @@ func calculateNovelStochastic(candles []Candle, period int) []float64 {
 	return stoch
 }
 
-// calculateTradeSummary returns a summary of trades.
-func calculateTradeSummary(trades []Trade) map[string]interface{} {
-	totalTrades := len(trades)
-	winningTrades := 0
-	totalProfit := 0.0
-	for _, t := range trades {
-		totalProfit += t.ProfitPct
-		if t.ProfitPct > 0 {
-			winningTrades++
-		}
-	}
-	avgProfit := 0.0
-	if totalTrades > 0 {
-		avgProfit = totalProfit / float64(totalTrades)
-	}
-	return map[string]interface{}{
-		"total_trades":         totalTrades,
-		"winning_trades":       winningTrades,
-		"total_profit_pct":     totalProfit,
-		"avg_profit_per_trade": avgProfit,
-	}
-}
-
 // generatePlots creates a simple price chart with trade markers and returns a base64-encoded PNG.
 // generatePlots creates a simple price chart with trade markers and returns a base64-encoded PNG.
 // generatePlots creates a simple price chart with trade markers and returns a base64-encoded PNG.
-func generatePlots(candles []Candle, trades []Trade, strategyName string, rsiPeriod, maPeriod int, tradeType string) (string, error) {
+func generatePlots(candles []Candle, trades []Trade, strategyName string, rsiPeriod, maPeriod int, tradeType string, useHeikinAshi bool) (string, error) {
 	p := plot.New()
-	p.Title.Text = fmt.Sprintf("%s Strategy - %s Trades", strategyName, tradeType)
+	p.Title.Text = fmt.Sprintf("%s Strategy - %s Trades%s", strategyName, tradeType, candleModeLabel(useHeikinAshi))
 	p.X.Label.Text = "Timestamp"
 	p.Y.Label.Text = "Price"
 
@@ -209,6 +263,22 @@ func generatePlots(candles []Candle, trades []Trade, strategyName string, rsiPer
 		}
 		tradeLine.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
 		p.Add(tradeLine)
+
+		// Draw the stop-loss/take-profit band the RiskManager computed at entry.
+		if trade.StopLossPrice != 0 {
+			slLine, err := plotter.NewLine(plotter.XYs{{X: entryX, Y: trade.StopLossPrice}, {X: exitX, Y: trade.StopLossPrice}})
+			if err == nil {
+				slLine.Color = color.RGBA{R: 200, A: 255}
+				p.Add(slLine)
+			}
+		}
+		if trade.TakeProfitPrice != 0 {
+			tpLine, err := plotter.NewLine(plotter.XYs{{X: entryX, Y: trade.TakeProfitPrice}, {X: exitX, Y: trade.TakeProfitPrice}})
+			if err == nil {
+				tpLine.Color = color.RGBA{G: 150, A: 255}
+				p.Add(tpLine)
+			}
+		}
 	}
 
 	// Save the plot to a temporary file.
@@ -236,10 +306,47 @@ func generatePlots(candles []Candle, trades []Trade, strategyName string, rsiPer
 type TradingStrategy struct {
 	user   User
 	client *binance.Client
+	// candlesOverride, when set, makes fetchData return this slice directly
+	// instead of hitting Binance or CSV. Used by Optimizer and Backtester to
+	// run a strategy against a specific train/test slice of candle history.
+	candlesOverride []Candle
+	// store, when set, receives every trade the strategy closes so a live
+	// session survives a restart. Left nil for one-off evaluations such as
+	// the Optimizer's train/test slices.
+	store Store
+	// exchange, when set, makes fetchData route through the Exchange
+	// adapter (see exchange.go) instead of the Binance client directly, so
+	// strategies work against MAX/OKX/CSV sessions too. Left nil keeps the
+	// pre-multi-exchange Binance-only path for callers that haven't been
+	// updated to use a session yet (e.g. Optimizer, stream tick evaluation).
+	exchange Exchange
 }
 
-// fetchData retrieves historical klines data from Binance (or loads from CSV if requested).
+// fetchData retrieves historical klines data through the configured
+// Exchange adapter (or loads from CSV if requested), falling back to the
+// legacy direct-Binance path when no exchange adapter is set.
 func (ts *TradingStrategy) fetchData(symbol, interval string, limit int, useCSV bool) ([]Candle, error) {
+	candles, err := ts.fetchRawData(symbol, interval, limit, useCSV)
+	if err != nil {
+		return nil, err
+	}
+	if ts.user.UseHeikinAshi {
+		candles = toHeikinAshi(candles)
+	}
+	return candles, nil
+}
+
+// fetchRawData is fetchData before the optional Heikin-Ashi transformation.
+func (ts *TradingStrategy) fetchRawData(symbol, interval string, limit int, useCSV bool) ([]Candle, error) {
+	if ts.candlesOverride != nil {
+		return ts.candlesOverride, nil
+	}
+	if ts.exchange != nil {
+		if useCSV {
+			return NewCSVExchange().FetchKlines(symbol, interval, limit)
+		}
+		return ts.exchange.FetchKlines(symbol, interval, limit)
+	}
 	if useCSV {
 		return loadCSVData("minute_data.csv")
 	}
@@ -332,35 +439,65 @@ func (ts *TradingStrategy) executeKage(symbol string, useCSV bool) (map[string]i
 	}
 	thresholdVol := meanVol * 1.5
 
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 
 	for i := window; i < len(candles); i++ {
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
 		currentVol := vol[i]
 		stochVal := stoch[i]
+
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("KAGE %s trade for %s closed by %s at %s (price: %.4f) | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), exitPrice, profit))
+			continue
+		}
+
 		if ts.user.TradeType == "long" {
 			if currentVol < thresholdVol && stochVal < 20 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
 			} else if currentVol > thresholdVol && stochVal > 80 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "long",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "long",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("KAGE Long trade for %s: Buy at %s (price: %.4f) | Sell at %s (price: %.4f) | P/L: %.2f%%",
@@ -368,22 +505,28 @@ func (ts *TradingStrategy) executeKage(symbol string, useCSV bool) (map[string]i
 			}
 		} else {
 			if currentVol < thresholdVol && stochVal > 80 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+				})
 			} else if currentVol > thresholdVol && stochVal < 20 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "short")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "short",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "short",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("KAGE Short trade for %s: Sell at %s (price: %.4f) | Cover at %s (price: %.4f) | P/L: %.2f%%",
@@ -392,8 +535,15 @@ func (ts *TradingStrategy) executeKage(symbol string, useCSV bool) (map[string]i
 		}
 	}
 
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "KAGE", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "KAGE", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -412,11 +562,10 @@ func (ts *TradingStrategy) executeKitsune(symbol string, useCSV bool) (map[strin
 	}
 	stoch := calculateNovelStochastic(candles, 14)
 	window := 20
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 
 	for i := window; i < len(candles); i++ {
 		// Compute z-score of close price over a rolling window.
@@ -438,24 +587,54 @@ func (ts *TradingStrategy) executeKitsune(symbol string, useCSV bool) (map[strin
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
 
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("KITSUNE %s trade for %s closed by %s at %s | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), profit))
+			continue
+		}
+
 		if ts.user.TradeType == "long" {
 			if zScore < -1.0 && stochVal < 20 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
 			} else if zScore > 1.0 && stochVal > 80 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "long",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "long",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("KITSUNE Long trade for %s: Buy at %s | Sell at %s | P/L: %.2f%%",
@@ -463,22 +642,28 @@ func (ts *TradingStrategy) executeKitsune(symbol string, useCSV bool) (map[strin
 			}
 		} else {
 			if zScore > 1.0 && stochVal > 80 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+				})
 			} else if zScore < -1.0 && stochVal < 20 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "short")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "short",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "short",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("KITSUNE Short trade for %s: Sell at %s | Cover at %s | P/L: %.2f%%",
@@ -487,8 +672,15 @@ func (ts *TradingStrategy) executeKitsune(symbol string, useCSV bool) (map[strin
 		}
 	}
 
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "KITSUNE", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "KITSUNE", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -512,11 +704,10 @@ func (ts *TradingStrategy) executeRyu(symbol string, useCSV bool) (map[string]in
 		returns[i] = math.Log(candles[i].Close / candles[i-1].Close)
 	}
 	window := 50
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 	for i := window; i < len(candles); i++ {
 		sum := 0.0
 		for j := i - window; j < i; j++ {
@@ -536,24 +727,54 @@ func (ts *TradingStrategy) executeRyu(symbol string, useCSV bool) (map[string]in
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
 
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("RYU %s trade for %s closed by %s at %s | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), profit))
+			continue
+		}
+
 		if ts.user.TradeType == "long" {
 			if zReturn < -1 && stochVal < 20 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
 			} else if zReturn > 1 && stochVal > 80 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "long",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "long",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("RYU Long trade for %s: Buy at %s | Sell at %s | P/L: %.2f%%",
@@ -561,22 +782,28 @@ func (ts *TradingStrategy) executeRyu(symbol string, useCSV bool) (map[string]in
 			}
 		} else {
 			if zReturn > 1 && stochVal > 80 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+				})
 			} else if zReturn < -1 && stochVal < 20 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "short")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "short",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "short",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("RYU Short trade for %s: Sell at %s | Cover at %s | P/L: %.2f%%",
@@ -584,8 +811,15 @@ func (ts *TradingStrategy) executeRyu(symbol string, useCSV bool) (map[string]in
 			}
 		}
 	}
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "RYU", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "RYU", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -604,11 +838,10 @@ func (ts *TradingStrategy) executeSakura(symbol string, useCSV bool) (map[string
 	}
 	stoch := calculateNovelStochastic(candles, 14)
 	window := 50
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 	// Helper: median of a slice of float64.
 	median := func(arr []float64) float64 {
 		sorted := append([]float64{}, arr...)
@@ -654,6 +887,31 @@ func (ts *TradingStrategy) executeSakura(symbol string, useCSV bool) (map[string
 		stochVal := stoch[i]
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
+
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("SAKURA %s trade for %s closed by %s at %s | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), profit))
+			continue
+		}
+
 		if len(upSegment) >= 2 && len(downSegment) >= 2 {
 			// Prepare x-values for regression.
 			xUp := make([]float64, len(upSegment))
@@ -674,22 +932,28 @@ func (ts *TradingStrategy) executeSakura(symbol string, useCSV bool) (map[string
 			threshold := currentPrice * 0.003
 			if ts.user.TradeType == "long" {
 				if deviation < threshold && stochVal < 20 && len(openPositions) == 0 {
-					openPositions = append(openPositions, struct {
-						EntryTime  time.Time
-						EntryPrice float64
-					}{currentTime, currentPrice})
+					riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+					openPositions = append(openPositions, openPosition{
+						EntryTime: currentTime, EntryPrice: currentPrice,
+						RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+						StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+						TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+					})
 				} else if deviation > threshold && stochVal > 80 && len(openPositions) > 0 {
 					pos := openPositions[0]
 					openPositions = openPositions[1:]
 					profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 					trade := Trade{
-						Symbol:     symbol,
-						EntryTime:  pos.EntryTime.Format(time.RFC3339),
-						EntryPrice: pos.EntryPrice,
-						ExitTime:   currentTime.Format(time.RFC3339),
-						ExitPrice:  currentPrice,
-						TradeType:  "long",
-						ProfitPct:  profit,
+						Symbol:          symbol,
+						EntryTime:       pos.EntryTime.Format(time.RFC3339),
+						EntryPrice:      pos.EntryPrice,
+						ExitTime:        currentTime.Format(time.RFC3339),
+						ExitPrice:       currentPrice,
+						TradeType:       "long",
+						ProfitPct:       profit,
+						ExitReason:      "signal",
+						StopLossPrice:   pos.StopLossPrice,
+						TakeProfitPrice: pos.TakeProfitPrice,
 					}
 					trades = append(trades, trade)
 					logTrade(fmt.Sprintf("SAKURA Long trade for %s: Buy at %s | Sell at %s | P/L: %.2f%%",
@@ -697,22 +961,28 @@ func (ts *TradingStrategy) executeSakura(symbol string, useCSV bool) (map[string
 				}
 			} else {
 				if deviation < threshold && stochVal > 80 && len(openPositions) == 0 {
-					openPositions = append(openPositions, struct {
-						EntryTime  time.Time
-						EntryPrice float64
-					}{currentTime, currentPrice})
+					riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+					openPositions = append(openPositions, openPosition{
+						EntryTime: currentTime, EntryPrice: currentPrice,
+						RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+						StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+						TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+					})
 				} else if deviation > threshold && stochVal < 20 && len(openPositions) > 0 {
 					pos := openPositions[0]
 					openPositions = openPositions[1:]
 					profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "short")
 					trade := Trade{
-						Symbol:     symbol,
-						EntryTime:  pos.EntryTime.Format(time.RFC3339),
-						EntryPrice: pos.EntryPrice,
-						ExitTime:   currentTime.Format(time.RFC3339),
-						ExitPrice:  currentPrice,
-						TradeType:  "short",
-						ProfitPct:  profit,
+						Symbol:          symbol,
+						EntryTime:       pos.EntryTime.Format(time.RFC3339),
+						EntryPrice:      pos.EntryPrice,
+						ExitTime:        currentTime.Format(time.RFC3339),
+						ExitPrice:       currentPrice,
+						TradeType:       "short",
+						ProfitPct:       profit,
+						ExitReason:      "signal",
+						StopLossPrice:   pos.StopLossPrice,
+						TakeProfitPrice: pos.TakeProfitPrice,
 					}
 					trades = append(trades, trade)
 					logTrade(fmt.Sprintf("SAKURA Short trade for %s: Sell at %s | Cover at %s | P/L: %.2f%%",
@@ -721,8 +991,15 @@ func (ts *TradingStrategy) executeSakura(symbol string, useCSV bool) (map[string
 			}
 		}
 	}
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "SAKURA", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "SAKURA", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -746,11 +1023,10 @@ func (ts *TradingStrategy) executeHikari(symbol string, useCSV bool) (map[string
 		returns[i] = math.Log(candles[i].Close / candles[i-1].Close)
 	}
 	window := 30
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 	for i := window; i < len(candles); i++ {
 		// For simplicity, use current return as momentum.
 		currentReturn := returns[i]
@@ -759,24 +1035,54 @@ func (ts *TradingStrategy) executeHikari(symbol string, useCSV bool) (map[string
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
 
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("HIKARI %s trade for %s closed by %s at %s | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), profit))
+			continue
+		}
+
 		if ts.user.TradeType == "long" {
 			if momentum > 0.0005 && stochVal < 20 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
 			} else if momentum < 0 && stochVal > 80 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "long",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "long",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("HIKARI Long trade for %s: Buy at %s | Sell at %s | P/L: %.2f%%",
@@ -784,22 +1090,28 @@ func (ts *TradingStrategy) executeHikari(symbol string, useCSV bool) (map[string
 			}
 		} else {
 			if momentum < -0.0005 && stochVal > 80 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+				})
 			} else if momentum > 0 && stochVal < 20 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "short")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "short",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "short",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("HIKARI Short trade for %s: Sell at %s | Cover at %s | P/L: %.2f%%",
@@ -807,8 +1119,15 @@ func (ts *TradingStrategy) executeHikari(symbol string, useCSV bool) (map[string
 			}
 		}
 	}
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "HIKARI", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "HIKARI", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -826,23 +1145,50 @@ func (ts *TradingStrategy) executeTenshi(symbol string, useCSV bool) (map[string
 		return map[string]interface{}{"error": fmt.Sprintf("No data fetched for %s", symbol)}, err
 	}
 	stoch := calculateNovelStochastic(candles, 14)
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 	// Detect local extrema: iterate from index 1 to len-2.
 	for i := 1; i < len(candles)-1; i++ {
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
 		stochVal := stoch[i]
+
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("TENSHI %s trade for %s closed by %s at %s | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), profit))
+			continue
+		}
+
 		// Local minimum
 		if candles[i].Close < candles[i-1].Close && candles[i].Close < candles[i+1].Close {
 			if ts.user.TradeType == "long" && stochVal < 20 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
 			}
 		}
 		// Local maximum
@@ -852,13 +1198,16 @@ func (ts *TradingStrategy) executeTenshi(symbol string, useCSV bool) (map[string
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "long",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "long",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("TENSHI Long trade for %s: Buy at %s | Sell at %s | P/L: %.2f%%",
@@ -866,8 +1215,15 @@ func (ts *TradingStrategy) executeTenshi(symbol string, useCSV bool) (map[string
 			}
 		}
 	}
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "TENSHI", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "TENSHI", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -886,11 +1242,10 @@ func (ts *TradingStrategy) executeZen(symbol string, useCSV bool) (map[string]in
 	}
 	stoch := calculateNovelStochastic(candles, 14)
 	window := 20
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
 	var trades []Trade
-	var openPositions []struct {
-		EntryTime  time.Time
-		EntryPrice float64
-	}
+	var openPositions []openPosition
 	// Pre-calculate SMA and standard deviation for Bollinger Bands.
 	sma := make([]float64, len(candles))
 	stdDev := make([]float64, len(candles))
@@ -940,24 +1295,54 @@ func (ts *TradingStrategy) executeZen(symbol string, useCSV bool) (map[string]in
 		currentTime := candles[i].DateTime
 		currentPrice := candles[i].Close
 
+		if len(openPositions) > 0 && openPositions[0].RiskExitIdx >= 0 && i >= openPositions[0].RiskExitIdx {
+			pos := openPositions[0]
+			openPositions = openPositions[1:]
+			exitPrice := candles[pos.RiskExitIdx].Close
+			exitTime := candles[pos.RiskExitIdx].DateTime
+			profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+			trade := Trade{
+				Symbol:          symbol,
+				EntryTime:       pos.EntryTime.Format(time.RFC3339),
+				EntryPrice:      pos.EntryPrice,
+				ExitTime:        exitTime.Format(time.RFC3339),
+				ExitPrice:       exitPrice,
+				TradeType:       ts.user.TradeType,
+				ProfitPct:       profit,
+				ExitReason:      pos.RiskExitReason,
+				StopLossPrice:   pos.StopLossPrice,
+				TakeProfitPrice: pos.TakeProfitPrice,
+			}
+			trades = append(trades, trade)
+			logTrade(fmt.Sprintf("ZEN %s trade for %s closed by %s at %s | P/L: %.2f%%",
+				ts.user.TradeType, symbol, pos.RiskExitReason, exitTime.Format(time.RFC3339), profit))
+			continue
+		}
+
 		if ts.user.TradeType == "long" {
 			if phase < 0.3 && stochVal < 20 && mom > 0 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
 			} else if phase > 0.7 && stochVal > 80 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "long")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "long",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "long",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("ZEN Long trade for %s: Buy at %s | Sell at %s | P/L: %.2f%%",
@@ -965,22 +1350,28 @@ func (ts *TradingStrategy) executeZen(symbol string, useCSV bool) (map[string]in
 			}
 		} else {
 			if phase > 0.7 && stochVal > 80 && mom < 0 && len(openPositions) == 0 {
-				openPositions = append(openPositions, struct {
-					EntryTime  time.Time
-					EntryPrice float64
-				}{currentTime, currentPrice})
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+				})
 			} else if phase < 0.3 && stochVal < 20 && len(openPositions) > 0 {
 				pos := openPositions[0]
 				openPositions = openPositions[1:]
 				profit := ts.safeProfitPct(pos.EntryPrice, currentPrice, "short")
 				trade := Trade{
-					Symbol:     symbol,
-					EntryTime:  pos.EntryTime.Format(time.RFC3339),
-					EntryPrice: pos.EntryPrice,
-					ExitTime:   currentTime.Format(time.RFC3339),
-					ExitPrice:  currentPrice,
-					TradeType:  "short",
-					ProfitPct:  profit,
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        currentTime.Format(time.RFC3339),
+					ExitPrice:       currentPrice,
+					TradeType:       "short",
+					ProfitPct:       profit,
+					ExitReason:      "signal",
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
 				}
 				trades = append(trades, trade)
 				logTrade(fmt.Sprintf("ZEN Short trade for %s: Sell at %s | Cover at %s | P/L: %.2f%%",
@@ -988,8 +1379,15 @@ func (ts *TradingStrategy) executeZen(symbol string, useCSV bool) (map[string]in
 			}
 		}
 	}
-	summary := calculateTradeSummary(trades)
-	plotImage, err := generatePlots(candles, trades, "ZEN", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType)
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "ZEN", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
 	if err != nil {
 		return nil, err
 	}
@@ -1000,205 +1398,41 @@ func (ts *TradingStrategy) executeZen(symbol string, useCSV bool) (map[string]in
 	}, nil
 }
 
-// RAMSEY strategy: a simplified implementation based on Ramsey numbers for systemic risk.
-func (ts *TradingStrategy) executeRamsey(symbol string, useCSV bool) (map[string]interface{}, error) {
-	// For the Ramsey strategy we simulate a multi-asset analysis.
-	// In practice you would load multiple assets’ time series and compute correlations.
-	// Here we simulate with N assets and a random correlation matrix.
-	N := 10
-	correlationThreshold := 0.7
-	targetCliqueSize := 4
-	targetIndependentSize := 4
-
-	// Generate a random symmetric correlation matrix.
-	matrix := make([][]float64, N)
-	for i := 0; i < N; i++ {
-		matrix[i] = make([]float64, N)
-		for j := 0; j < N; j++ {
-			if i == j {
-				matrix[i][j] = 1.0
-			} else if j < i {
-				matrix[i][j] = matrix[j][i]
-			} else {
-				matrix[i][j] = rand.Float64()
-			}
-		}
-	}
-	// Build graph: edge exists if correlation >= threshold.
-	adj := make([][]bool, N)
-	for i := 0; i < N; i++ {
-		adj[i] = make([]bool, N)
-		for j := 0; j < N; j++ {
-			if i != j && matrix[i][j] >= correlationThreshold {
-				adj[i][j] = true
-			} else {
-				adj[i][j] = false
-			}
-		}
-	}
-	// Bron–Kerbosch algorithm to find maximum clique.
-	maxClique := []int{}
-	var bronKerbosch func(r, p, x []int)
-	bronKerbosch = func(r, p, x []int) {
-		if len(p) == 0 && len(x) == 0 {
-			if len(r) > len(maxClique) {
-				cp := make([]int, len(r))
-				copy(cp, r)
-				maxClique = cp
-			}
-			return
-		}
-		for i := 0; i < len(p); i++ {
-			v := p[i]
-			// Compute neighbors of v.
-			var nv []int
-			for j := 0; j < N; j++ {
-				if adj[v][j] {
-					nv = append(nv, j)
-				}
-			}
-			// Intersection of p and nv.
-			var pNew []int
-			for _, w := range p {
-				for _, u := range nv {
-					if w == u {
-						pNew = append(pNew, w)
-						break
-					}
-				}
-			}
-			// Intersection of x and nv.
-			var xNew []int
-			for _, w := range x {
-				for _, u := range nv {
-					if w == u {
-						xNew = append(xNew, w)
-						break
-					}
-				}
-			}
-			bronKerbosch(append(r, v), pNew, xNew)
-			// Remove v from p and add to x.
-			p = append(p[:i], p[i+1:]...)
-			x = append(x, v)
-			i--
-		}
-	}
-	// Initial call: r empty, p = all vertices, x empty.
-	allVertices := make([]int, N)
-	for i := 0; i < N; i++ {
-		allVertices[i] = i
-	}
-	bronKerbosch([]int{}, allVertices, []int{})
-	maxCliqueSize := len(maxClique)
-
-	// For independent set, use complement graph.
-	adjComplement := make([][]bool, N)
-	for i := 0; i < N; i++ {
-		adjComplement[i] = make([]bool, N)
-		for j := 0; j < N; j++ {
-			if i != j && !adj[i][j] {
-				adjComplement[i][j] = true
-			} else {
-				adjComplement[i][j] = false
-			}
-		}
-	}
-	// Reuse Bron-Kerbosch on complement graph.
-	maxIndependent := []int{}
-	var bronKerboschComp func(r, p, x []int)
-	bronKerboschComp = func(r, p, x []int) {
-		if len(p) == 0 && len(x) == 0 {
-			if len(r) > len(maxIndependent) {
-				cp := make([]int, len(r))
-				copy(cp, r)
-				maxIndependent = cp
-			}
-			return
-		}
-		for i := 0; i < len(p); i++ {
-			v := p[i]
-			var nv []int
-			for j := 0; j < N; j++ {
-				if adjComplement[v][j] {
-					nv = append(nv, j)
-				}
-			}
-			var pNew []int
-			for _, w := range p {
-				for _, u := range nv {
-					if w == u {
-						pNew = append(pNew, w)
-						break
-					}
-				}
-			}
-			var xNew []int
-			for _, w := range x {
-				for _, u := range nv {
-					if w == u {
-						xNew = append(xNew, w)
-						break
-					}
-				}
-			}
-			bronKerboschComp(append(r, v), pNew, xNew)
-			p = append(p[:i], p[i+1:]...)
-			x = append(x, v)
-			i--
-		}
-	}
-	bronKerboschComp([]int{}, allVertices, []int{})
-	maxIndependentSize := len(maxIndependent)
-
-	// Generate signal.
-	signal := "Neutral"
-	if maxCliqueSize >= targetCliqueSize {
-		signal = "High Systemic Risk (risk-off)"
-	} else if maxIndependentSize >= targetIndependentSize {
-		signal = "Arbitrage/Diversification Opportunity (risk-on)"
-	}
-
-	// No trades are simulated here; just a signal summary.
-	result := map[string]interface{}{
-		"signal":               signal,
-		"max_clique_size":      maxCliqueSize,
-		"max_independent_size": maxIndependentSize,
-		"correlation_matrix":   matrix, // for reference
-	}
-	return result, nil
-}
-
-// executeStrategy dispatches the chosen strategy.
+// executeStrategy dispatches the chosen strategy by looking it up in the
+// global strategy registry (see registry.go) instead of switching on its
+// name, so a new Strategy only has to call RegisterStrategy from its own
+// file's init().
 func (ts *TradingStrategy) executeStrategy(symbol string, useScratchRSI bool, useCSV bool) (map[string]interface{}, error) {
 	switch ts.user.Strategy {
 	case "RSI", "MA", "RSI_MA":
 		// RSI-based strategies not fully implemented in this Go example.
 		return map[string]interface{}{"error": "RSI/MA-based strategies not implemented in this Go example."}, nil
-	case "KAGE":
-		return ts.executeKage(symbol, useCSV)
-	case "KITSUNE":
-		return ts.executeKitsune(symbol, useCSV)
-	case "RYU":
-		return ts.executeRyu(symbol, useCSV)
-	case "SAKURA":
-		return ts.executeSakura(symbol, useCSV)
-	case "HIKARI":
-		return ts.executeHikari(symbol, useCSV)
-	case "TENSHI":
-		return ts.executeTenshi(symbol, useCSV)
-	case "ZEN":
-		return ts.executeZen(symbol, useCSV)
-	case "RAMSEY":
-		return ts.executeRamsey(symbol, useCSV)
-	default:
+	}
+
+	strat, ok := lookupStrategy(ts.user.Strategy)
+	if !ok {
 		return map[string]interface{}{"error": "Unknown strategy specified."}, nil
 	}
+	result, err := strat.Execute(context.Background(), ts, symbol, ts.candlesOverride, useCSV)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, err
+	}
+	if result.Error != "" {
+		return map[string]interface{}{"error": result.Error}, nil
+	}
+	return map[string]interface{}{
+		"trades":  result.Trades,
+		"plot":    result.Plot,
+		"summary": result.Summary,
+	}, nil
 }
 
 // ---------------------- HTTP Endpoints ----------------------
 
 func main() {
+	configPath := flag.String("config", "", "path to a YAML strategy config (see config/example.yaml) to run on startup")
+	flag.Parse()
+
 	// Set up logging to file.
 	logFile, err := os.OpenFile("trades.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
@@ -1212,6 +1446,25 @@ func main() {
 	// Create a Binance client (using empty strings for public data).
 	binanceClient := binance.NewClient("", "")
 
+	// Session manager: one Exchange adapter per venue (see exchange.go).
+	sessions := NewSessionManager(binanceClient)
+
+	// Persistent trade/position/equity store (see store.go for backends).
+	store, err := NewStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Run any strategies declared by --config once at startup, alongside
+	// (not instead of) the HTTP endpoints below.
+	if *configPath != "" {
+		cfg, err := LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		RunConfig(cfg, binanceClient, store, sessions)
+	}
+
 	// POST /trade endpoint.
 	router.POST("/trade", func(c *gin.Context) {
 		var req struct {
@@ -1226,6 +1479,36 @@ func main() {
 			MAPeriod      int     `json:"ma_period"`
 			UseScratchRSI bool    `json:"use_scratch_rsi"`
 			UseCSV        bool    `json:"use_csv"`
+
+			ATRWindow               int       `json:"atr_window"`
+			StopLossFactor          float64   `json:"stop_loss_factor"`
+			TakeProfitFactor        float64   `json:"take_profit_factor"`
+			TrailingActivationRatio []float64 `json:"trailing_activation_ratio"`
+			TrailingCallbackRate    []float64 `json:"trailing_callback_rate"`
+
+			Interval  string  `json:"interval"`
+			Window    int     `json:"window"`
+			FastMA    int     `json:"fast_ma"`
+			SlowMA    int     `json:"slow_ma"`
+			Threshold float64 `json:"threshold"`
+			PivotK    int     `json:"pivot_k"`
+
+			TriPaths       []TriPath `json:"tri_paths"`
+			MinSpreadRatio float64   `json:"min_spread_ratio"`
+			TakerFeeRate   float64   `json:"taker_fee_rate"`
+
+			RoiStopLossPercentage   float64 `json:"roi_stop_loss_percentage"`
+			RoiTakeProfitPercentage float64 `json:"roi_take_profit_percentage"`
+			ATRStopMultiplier       float64 `json:"atr_stop_multiplier"`
+
+			RamseySymbols               []string `json:"ramsey_symbols"`
+			RamseyWindow                int      `json:"ramsey_window"`
+			RamseyCorrelationThreshold  float64  `json:"ramsey_correlation_threshold"`
+			RamseyTargetCliqueSize      int      `json:"ramsey_target_clique_size"`
+			RamseyTargetIndependentSize int      `json:"ramsey_target_independent_size"`
+			RamseyCadenceSeconds        int      `json:"ramsey_cadence_seconds"`
+
+			UseHeikinAshi bool `json:"use_heikin_ashi"`
 		}
 		if err := c.BindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -1258,24 +1541,52 @@ func main() {
 		}
 
 		user := User{
-			Username:      req.Username,
-			RSIPeriod:     req.RSIPeriod,
-			BuyThreshold:  req.BuyThreshold,
-			SellThreshold: req.SellThreshold,
-			TradeType:     req.TradeType,
-			Strategy:      req.Strategy,
-			MAPeriod:      req.MAPeriod,
+			Username:                req.Username,
+			RSIPeriod:               req.RSIPeriod,
+			BuyThreshold:            req.BuyThreshold,
+			SellThreshold:           req.SellThreshold,
+			TradeType:               req.TradeType,
+			Strategy:                req.Strategy,
+			MAPeriod:                req.MAPeriod,
+			ATRWindow:               req.ATRWindow,
+			StopLossFactor:          req.StopLossFactor,
+			TakeProfitFactor:        req.TakeProfitFactor,
+			TrailingActivationRatio: req.TrailingActivationRatio,
+			TrailingCallbackRate:    req.TrailingCallbackRate,
+			Interval:                req.Interval,
+			Window:                  req.Window,
+			FastMA:                  req.FastMA,
+			SlowMA:                  req.SlowMA,
+			Threshold:               req.Threshold,
+			PivotK:                  req.PivotK,
+			TriPaths:                req.TriPaths,
+			MinSpreadRatio:          req.MinSpreadRatio,
+			TakerFeeRate:            req.TakerFeeRate,
+			Exits: ExitRules{
+				RoiStopLossPercentage:   req.RoiStopLossPercentage,
+				RoiTakeProfitPercentage: req.RoiTakeProfitPercentage,
+				ATRStopMultiplier:       req.ATRStopMultiplier,
+			},
+			RamseySymbols:               req.RamseySymbols,
+			RamseyWindow:                req.RamseyWindow,
+			RamseyCorrelationThreshold:  req.RamseyCorrelationThreshold,
+			RamseyTargetCliqueSize:      req.RamseyTargetCliqueSize,
+			RamseyTargetIndependentSize: req.RamseyTargetIndependentSize,
+			RamseyCadenceSeconds:        req.RamseyCadenceSeconds,
+			UseHeikinAshi:               req.UseHeikinAshi,
 		}
 
-		// For this implementation only "binance" is supported.
-		if req.Exchange != "binance" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Only 'binance' exchange is supported in this implementation."})
+		exchange, ok := sessions.Get(req.Exchange)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown exchange %q. Available: %v", req.Exchange, sessions.Names())})
 			return
 		}
 
 		strategyObj := TradingStrategy{
-			user:   user,
-			client: binanceClient,
+			user:     user,
+			client:   binanceClient,
+			store:    store,
+			exchange: exchange,
 		}
 		result, err := strategyObj.executeStrategy(req.Symbol, req.UseScratchRSI, req.UseCSV)
 		if err != nil {
@@ -1287,30 +1598,40 @@ func main() {
 
 	// GET /exchanges endpoint.
 	router.GET("/exchanges", func(c *gin.Context) {
-		// Only Binance is supported.
-		c.JSON(http.StatusOK, gin.H{"exchanges": []string{"binance"}})
+		c.JSON(http.StatusOK, gin.H{"exchanges": sessions.Names()})
 	})
 
 	// GET /symbols endpoint.
 	router.GET("/symbols", func(c *gin.Context) {
 		exchangeID := c.Query("exchange")
-		if exchangeID == "" || exchangeID != "binance" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Please provide exchange=binance as a parameter."})
+		exchange, ok := sessions.Get(exchangeID)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Please provide a valid exchange parameter. Available: %v", sessions.Names())})
 			return
 		}
-		// Fetch exchange info from Binance.
-		exInfo, err := binanceClient.NewExchangeInfoService().Do(context.Background())
+		symbols, err := exchange.ExchangeInfo()
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
-		var symbols []string
-		for _, sym := range exInfo.Symbols {
-			symbols = append(symbols, sym.Symbol)
-		}
-		c.JSON(http.StatusOK, gin.H{"exchange": "binance", "symbols": symbols})
+		c.JSON(http.StatusOK, gin.H{"exchange": exchangeID, "symbols": symbols})
 	})
 
+	// POST /optimize endpoint: walk-forward parameter optimization.
+	registerOptimizerEndpoint(router, NewOptimizer(binanceClient))
+
+	// GET /stream/:strategy/:symbol endpoint: live trade events over SSE.
+	registerStreamEndpoint(router, binanceClient, store)
+
+	// GET /trades, GET /positions, GET /equity: read the persistent store.
+	registerStoreEndpoints(router, store)
+
+	// GET /ramsey/matrix: current RAMSEY correlation matrix/clique/independent set.
+	registerRamseyEndpoint(router, sessions)
+
+	// POST /backtest: deterministic CSV replay with fee-adjusted performance metrics.
+	registerBacktestEndpoint(router, NewBacktester(binanceClient, store))
+
 	// Run server on port 8080.
 	router.Run(":8080")
 }
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/gin-gonic/gin"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette/moreland"
+	"gonum.org/v1/plot/plotter"
+)
+
+// Optimizer walk-forward tests a strategy's parameters: for each fold it
+// grid-searches paramGrid on the train slice, then evaluates the winning
+// combination out-of-sample on the test slice.
+type Optimizer struct {
+	client *binance.Client
+}
+
+// NewOptimizer builds an Optimizer sharing the Binance client main() creates.
+func NewOptimizer(client *binance.Client) *Optimizer {
+	return &Optimizer{client: client}
+}
+
+// OptimizerFoldResult is the per-fold outcome of a walk-forward run.
+type OptimizerFoldResult struct {
+	Fold         int                `json:"fold"`
+	BestParams   map[string]float64 `json:"best_params"`
+	TrainSummary TradeStats         `json:"train_summary"`
+	TestSummary  TradeStats         `json:"test_summary"`
+}
+
+// OptimizerResult is the full walk-forward report returned by Run.
+type OptimizerResult struct {
+	Folds     []OptimizerFoldResult  `json:"folds"`
+	Aggregate map[string]interface{} `json:"aggregate"`
+	Heatmap   string                 `json:"heatmap,omitempty"`
+}
+
+// Run slices candle history for symbol into rolling train/test windows (a
+// 70/30 split within each of the folds segments), grid-searches paramGrid on
+// each train slice by re-running strategyName via executeStrategy with an
+// injected User, and evaluates the winning parameter set out-of-sample on
+// the test slice.
+func (o *Optimizer) Run(strategyName, symbol string, paramGrid map[string][]float64, folds int, useCSV bool) (*OptimizerResult, error) {
+	if folds <= 0 {
+		folds = 1
+	}
+	probe := &TradingStrategy{client: o.client}
+	candles, err := probe.fetchData(symbol, "1m", 10000, useCSV)
+	if err != nil || len(candles) == 0 {
+		return nil, fmt.Errorf("no data fetched for %s", symbol)
+	}
+
+	foldSize := len(candles) / folds
+	if foldSize < 10 {
+		return nil, fmt.Errorf("not enough candles (%d) to form %d folds", len(candles), folds)
+	}
+	combos := expandParamGrid(paramGrid)
+	if len(combos) == 0 {
+		combos = []map[string]float64{{}}
+	}
+
+	var foldResults []OptimizerFoldResult
+	var heatmapScores []float64 // best train profit per fold, for a quick heatmap
+	for f := 0; f < folds; f++ {
+		start := f * foldSize
+		end := start + foldSize
+		if f == folds-1 {
+			end = len(candles)
+		}
+		mid := start + (end-start)*7/10
+		if mid <= start+1 || end <= mid+1 {
+			continue
+		}
+		trainCandles := candles[start:mid]
+		testCandles := candles[mid:end]
+
+		bestProfit := math.Inf(-1)
+		var bestParams map[string]float64
+		var bestTrainSummary TradeStats
+		for _, params := range combos {
+			user := buildOptimizerUser(strategyName, params)
+			strat := &TradingStrategy{user: user, client: o.client, candlesOverride: trainCandles}
+			result, err := strat.executeStrategy(symbol, false, useCSV)
+			if err != nil {
+				continue
+			}
+			stats, ok := result["summary"].(TradeStats)
+			if !ok {
+				continue
+			}
+			if stats.TotalProfitPct > bestProfit {
+				bestProfit = stats.TotalProfitPct
+				bestParams = params
+				bestTrainSummary = stats
+			}
+		}
+		if bestParams == nil {
+			continue
+		}
+		heatmapScores = append(heatmapScores, bestProfit)
+
+		testUser := buildOptimizerUser(strategyName, bestParams)
+		testStrat := &TradingStrategy{user: testUser, client: o.client, candlesOverride: testCandles}
+		testResult, err := testStrat.executeStrategy(symbol, false, useCSV)
+		var testSummary TradeStats
+		if err == nil {
+			testSummary, _ = testResult["summary"].(TradeStats)
+		}
+
+		foldResults = append(foldResults, OptimizerFoldResult{
+			Fold:         f + 1,
+			BestParams:   bestParams,
+			TrainSummary: bestTrainSummary,
+			TestSummary:  testSummary,
+		})
+	}
+
+	aggregate := aggregateFoldResults(foldResults)
+	heatmap, _ := generateOptimizerHeatmap(heatmapScores)
+
+	return &OptimizerResult{Folds: foldResults, Aggregate: aggregate, Heatmap: heatmap}, nil
+}
+
+// buildOptimizerUser maps a flat parameter combination onto the User fields
+// each strategy reads its knobs from.
+func buildOptimizerUser(strategyName string, params map[string]float64) User {
+	user := User{Strategy: strategyName, TradeType: "long"}
+	if v, ok := params["RSIPeriod"]; ok {
+		user.RSIPeriod = int(v)
+	}
+	if v, ok := params["MAPeriod"]; ok {
+		user.MAPeriod = int(v)
+	}
+	if v, ok := params["BuyThreshold"]; ok {
+		user.BuyThreshold = v
+	}
+	if v, ok := params["SellThreshold"]; ok {
+		user.SellThreshold = v
+	}
+	if v, ok := params["ATRWindow"]; ok {
+		user.ATRWindow = int(v)
+	}
+	if v, ok := params["ATRStopFactor"]; ok {
+		user.StopLossFactor = v
+	}
+	if v, ok := params["ATRTakeProfitFactor"]; ok {
+		user.TakeProfitFactor = v
+	}
+	return user
+}
+
+// expandParamGrid turns a map of parameter name -> candidate values into the
+// cartesian product of all combinations.
+func expandParamGrid(paramGrid map[string][]float64) []map[string]float64 {
+	keys := make([]string, 0, len(paramGrid))
+	for k := range paramGrid {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	combos := []map[string]float64{{}}
+	for _, key := range keys {
+		values := paramGrid[key]
+		var next []map[string]float64
+		for _, combo := range combos {
+			for _, v := range values {
+				c := make(map[string]float64, len(combo)+1)
+				for k, existing := range combo {
+					c[k] = existing
+				}
+				c[key] = v
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// aggregateFoldResults averages the out-of-sample test summary across folds.
+func aggregateFoldResults(folds []OptimizerFoldResult) map[string]interface{} {
+	if len(folds) == 0 {
+		return map[string]interface{}{"folds_run": 0}
+	}
+	var totalProfit, totalWinRate float64
+	var count int
+	for _, f := range folds {
+		if f.TestSummary.TotalTrades == 0 {
+			continue
+		}
+		totalProfit += f.TestSummary.TotalProfitPct
+		totalWinRate += float64(f.TestSummary.WinningTrades) / float64(f.TestSummary.TotalTrades)
+		count++
+	}
+	if count == 0 {
+		return map[string]interface{}{"folds_run": len(folds)}
+	}
+	return map[string]interface{}{
+		"folds_run":             len(folds),
+		"avg_test_profit_pct":   totalProfit / float64(count),
+		"avg_test_win_rate_pct": (totalWinRate / float64(count)) * 100,
+	}
+}
+
+// generateOptimizerHeatmap renders the best in-sample profit per fold as a
+// single-row heatmap and returns it as a base64-encoded PNG.
+func generateOptimizerHeatmap(scores []float64) (string, error) {
+	if len(scores) == 0 {
+		return "", nil
+	}
+	grid := heatmapGrid{scores: scores}
+	p := plot.New()
+	p.Title.Text = "Optimizer: best in-sample profit % per fold"
+	heatMap := plotter.NewHeatMap(grid, moreland.SmoothBlueRed().Palette(32))
+	p.Add(heatMap)
+
+	tmpFile, err := os.CreateTemp("", "optimizer-heatmap-*.png")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := p.Save(400, 120, tmpFile.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// heatmapGrid adapts a flat score slice to plotter.GridXYZ as a single row.
+type heatmapGrid struct {
+	scores []float64
+}
+
+func (g heatmapGrid) Dims() (c, r int)   { return len(g.scores), 1 }
+func (g heatmapGrid) X(c int) float64    { return float64(c) }
+func (g heatmapGrid) Y(r int) float64    { return float64(r) }
+func (g heatmapGrid) Z(c, r int) float64 { return g.scores[c] }
+
+// registerOptimizerEndpoint wires POST /optimize into the given router.
+func registerOptimizerEndpoint(router *gin.Engine, optimizer *Optimizer) {
+	router.POST("/optimize", func(c *gin.Context) {
+		var req struct {
+			Strategy  string               `json:"strategy"`
+			Symbol    string               `json:"symbol"`
+			ParamGrid map[string][]float64 `json:"param_grid"`
+			Folds     int                  `json:"folds"`
+			UseCSV    bool                 `json:"use_csv"`
+		}
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Folds == 0 {
+			req.Folds = 3
+		}
+		result, err := optimizer.Run(req.Strategy, req.Symbol, req.ParamGrid, req.Folds, req.UseCSV)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, result)
+	})
+}
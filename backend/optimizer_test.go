@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestExpandParamGrid(t *testing.T) {
+	combos := expandParamGrid(map[string][]float64{
+		"RSIPeriod":    {14, 21},
+		"BuyThreshold": {30},
+	})
+	if len(combos) != 2 {
+		t.Fatalf("expandParamGrid returned %d combos, want 2", len(combos))
+	}
+	for _, c := range combos {
+		if c["BuyThreshold"] != 30 {
+			t.Errorf("combo %v: BuyThreshold = %v, want 30", c, c["BuyThreshold"])
+		}
+		if c["RSIPeriod"] != 14 && c["RSIPeriod"] != 21 {
+			t.Errorf("combo %v: RSIPeriod = %v, want 14 or 21", c, c["RSIPeriod"])
+		}
+	}
+}
+
+func TestExpandParamGridEmpty(t *testing.T) {
+	combos := expandParamGrid(map[string][]float64{})
+	if len(combos) != 1 || len(combos[0]) != 0 {
+		t.Fatalf("expandParamGrid({}) = %v, want a single empty combo", combos)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+)
+
+// Result is the normalized output of a registered Strategy's Execute call.
+// Error is set (with Trades/Plot/Summary left zero) when the strategy
+// reports a soft failure, such as not having enough candle history, rather
+// than a hard Go error.
+type Result struct {
+	Trades  []Trade
+	Plot    string
+	Summary TradeStats
+	Error   string
+}
+
+// Strategy is the pluggable strategy interface: a strategy declares its ID,
+// validates its config params, and runs against an explicit candle series.
+// Modeled on bbgo's strategy registry so a third-party strategy can be added
+// in a single file, by calling RegisterStrategy from an init(), without
+// touching executeStrategy's dispatch.
+type Strategy interface {
+	ID() string
+	Validate(params map[string]interface{}) error
+	Execute(ctx context.Context, ts *TradingStrategy, symbol string, candles []Candle, useCSV bool) (Result, error)
+}
+
+var strategyRegistry = map[string]Strategy{}
+
+// RegisterStrategy adds s to the global registry under s.ID(), overwriting
+// any previous registration with the same ID.
+func RegisterStrategy(s Strategy) {
+	strategyRegistry[s.ID()] = s
+}
+
+// lookupStrategy returns the registered Strategy for id, if any.
+func lookupStrategy(id string) (Strategy, bool) {
+	s, ok := strategyRegistry[id]
+	return s, ok
+}
+
+// legacyStrategy adapts one of the pre-registry execute* methods, which read
+// their parameters from User rather than a params map, to the Strategy
+// interface. This is what lets KAGE/KITSUNE/RYU/SAKURA/HIKARI/TENSHI/ZEN/
+// RAMSEY join the registry without a rewrite.
+type legacyStrategy struct {
+	id      string
+	execute func(ts *TradingStrategy, symbol string, useCSV bool) (map[string]interface{}, error)
+}
+
+func (l legacyStrategy) ID() string { return l.id }
+
+// Validate is a no-op: legacy strategies read their knobs from User, which
+// is already defaulted by the /trade request binding or userFromParams.
+func (l legacyStrategy) Validate(params map[string]interface{}) error { return nil }
+
+func (l legacyStrategy) Execute(ctx context.Context, ts *TradingStrategy, symbol string, candles []Candle, useCSV bool) (Result, error) {
+	strat := ts
+	if candles != nil {
+		strat = &TradingStrategy{user: ts.user, client: ts.client, store: ts.store, candlesOverride: candles}
+	}
+	raw, err := l.execute(strat, symbol, useCSV)
+	if err != nil {
+		return Result{}, err
+	}
+	return resultFromMap(raw), nil
+}
+
+// resultFromMap converts one of the legacy execute* map[string]interface{}
+// returns into a Result, including the soft "error" shape strategies return
+// when they can't fetch enough data.
+func resultFromMap(raw map[string]interface{}) Result {
+	var res Result
+	if errMsg, ok := raw["error"].(string); ok {
+		res.Error = errMsg
+		return res
+	}
+	if trades, ok := raw["trades"].([]Trade); ok {
+		res.Trades = trades
+	}
+	if plot, ok := raw["plot"].(string); ok {
+		res.Plot = plot
+	}
+	if summary, ok := raw["summary"].(TradeStats); ok {
+		res.Summary = summary
+	}
+	return res
+}
+
+func init() {
+	RegisterStrategy(legacyStrategy{id: "KAGE", execute: (*TradingStrategy).executeKage})
+	RegisterStrategy(legacyStrategy{id: "KITSUNE", execute: (*TradingStrategy).executeKitsune})
+	RegisterStrategy(legacyStrategy{id: "RYU", execute: (*TradingStrategy).executeRyu})
+	RegisterStrategy(legacyStrategy{id: "SAKURA", execute: (*TradingStrategy).executeSakura})
+	RegisterStrategy(legacyStrategy{id: "HIKARI", execute: (*TradingStrategy).executeHikari})
+	RegisterStrategy(legacyStrategy{id: "TENSHI", execute: (*TradingStrategy).executeTenshi})
+	RegisterStrategy(legacyStrategy{id: "ZEN", execute: (*TradingStrategy).executeZen})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// openPosition tracks an entry plus the pre-computed risk-managed exit that
+// RiskManager.ApplyExits found for it, shared across the strategy
+// implementations so each only has to append/pop it.
+type openPosition struct {
+	EntryTime       time.Time
+	EntryPrice      float64
+	RiskExitIdx     int
+	RiskExitReason  string
+	StopLossPrice   float64
+	TakeProfitPrice float64
+	// MaxFavorable is the best close price seen since entry (lowest for a
+	// short), maintained by ApplyExits' forward walk and read by shouldExit
+	// to arm/check the trailing-stop tiers.
+	MaxFavorable float64
+}
+
+// ExitRules bundles the exit conditions shouldExit checks for an open
+// position, on top of the opposite-signal exit every strategy already has:
+// an absolute ROI stop/take-profit, a symmetric ATR-band breach around
+// entry, and a multi-tier trailing stop where each TrailingActivationRatio
+// reached arms the matching (tighter) TrailingCallbackRate, bbgo-drift
+// style. A zero-valued field disables that particular check.
+type ExitRules struct {
+	RoiStopLossPercentage   float64
+	RoiTakeProfitPercentage float64
+	ATRStopMultiplier       float64
+	ATRWindow               int
+	TrailingActivationRatio []float64
+	TrailingCallbackRate    []float64
+}
+
+// shouldExit is the single shared exit check every strategy's RiskManager
+// walk runs per candle, ahead of its own opposite-signal exit: absolute ROI,
+// then the ATR band, then the trailing-stop tiers. pos.MaxFavorable must
+// already reflect the best price seen up to and including candle.
+func shouldExit(pos openPosition, candle Candle, atr float64, rules ExitRules, tradeType string) (bool, string) {
+	price := candle.Close
+	roi := (price - pos.EntryPrice) / pos.EntryPrice
+	if tradeType == "short" {
+		roi = -roi
+	}
+	if rules.RoiStopLossPercentage > 0 && roi <= -rules.RoiStopLossPercentage {
+		return true, "roi_stop_loss"
+	}
+	if rules.RoiTakeProfitPercentage > 0 && roi >= rules.RoiTakeProfitPercentage {
+		return true, "roi_take_profit"
+	}
+
+	if rules.ATRStopMultiplier > 0 {
+		band := rules.ATRStopMultiplier * atr
+		if (tradeType == "long" && price <= pos.EntryPrice-band) ||
+			(tradeType == "short" && price >= pos.EntryPrice+band) {
+			return true, "atr_band"
+		}
+	}
+
+	if len(rules.TrailingActivationRatio) > 0 && pos.MaxFavorable != 0 {
+		move := (pos.MaxFavorable - pos.EntryPrice) / pos.EntryPrice
+		if tradeType == "short" {
+			move = -move
+		}
+		tier := -1
+		for t, ratio := range rules.TrailingActivationRatio {
+			if move >= ratio {
+				tier = t
+			}
+		}
+		if tier >= 0 && tier < len(rules.TrailingCallbackRate) {
+			callback := rules.TrailingCallbackRate[tier] * pos.MaxFavorable
+			if (tradeType == "long" && price <= pos.MaxFavorable-callback) ||
+				(tradeType == "short" && price >= pos.MaxFavorable+callback) {
+				return true, "trailing_stop"
+			}
+		}
+	}
+
+	return false, ""
+}
+
+// RiskManager computes ATR-based stop-loss, take-profit, and multi-tier
+// trailing-stop exits for an open position. KAGE, KITSUNE, RYU, SAKURA,
+// HIKARI, TENSHI, ZEN and IRR construct one from the active User's risk
+// knobs and call ApplyExits instead of only closing on the opposite
+// indicator signal; HARMONIC, TRI and RAMSEY do not use it.
+type RiskManager struct {
+	ATRWindow        int
+	StopLossFactor   float64
+	TakeProfitFactor float64
+	// Exits layers the ROI/ATR-band/trailing checks shouldExit evaluates on
+	// top of the StopLossFactor/TakeProfitFactor band above; its
+	// TrailingActivationRatio/TrailingCallbackRate are what used to live
+	// directly on RiskManager, now routed through the one shared helper.
+	Exits ExitRules
+}
+
+// calculateATR computes a rolling Average True Range using Wilder smoothing
+// over the given window.
+func calculateATR(candles []Candle, window int) []float64 {
+	atr := make([]float64, len(candles))
+	if len(candles) == 0 {
+		return atr
+	}
+	tr := make([]float64, len(candles))
+	for i := range candles {
+		if i == 0 {
+			tr[i] = candles[i].High - candles[i].Low
+			continue
+		}
+		highLow := candles[i].High - candles[i].Low
+		highClose := math.Abs(candles[i].High - candles[i-1].Close)
+		lowClose := math.Abs(candles[i].Low - candles[i-1].Close)
+		tr[i] = math.Max(highLow, math.Max(highClose, lowClose))
+	}
+	for i := range candles {
+		switch {
+		case i == 0:
+			atr[i] = tr[i]
+		case i < window:
+			sum := 0.0
+			for j := 0; j <= i; j++ {
+				sum += tr[j]
+			}
+			atr[i] = sum / float64(i+1)
+		default:
+			atr[i] = (atr[i-1]*float64(window-1) + tr[i]) / float64(window)
+		}
+	}
+	return atr
+}
+
+// ApplyExits walks the candle series forward from entryIdx and returns the
+// index/price/reason of the first exit condition to fire: shouldExit's ROI,
+// ATR-band, and trailing-stop checks (rm.Exits), then this RiskManager's own
+// asymmetric ATR stop-loss/take-profit band. It returns exitIdx -1 if none of
+// the rules fire before the series ends, meaning the caller's own
+// signal-based exit should be used instead.
+func (rm *RiskManager) ApplyExits(candles []Candle, entryIdx int, entryPrice float64, tradeType string) (exitIdx int, exitPrice float64, reason string) {
+	if entryIdx < 0 || entryIdx >= len(candles) {
+		return -1, 0, ""
+	}
+	atr := calculateATR(candles, rm.ATRWindow)
+	entryATR := atr[entryIdx]
+
+	var stopPrice, takeProfitPrice float64
+	if tradeType == "long" {
+		stopPrice = entryPrice - rm.StopLossFactor*entryATR
+		takeProfitPrice = entryPrice + rm.TakeProfitFactor*entryATR
+	} else {
+		stopPrice = entryPrice + rm.StopLossFactor*entryATR
+		takeProfitPrice = entryPrice - rm.TakeProfitFactor*entryATR
+	}
+
+	// ExitRules may use its own ATR window for the band check (rm.Exits.ATRWindow
+	// defaults to rm.ATRWindow in TradingStrategy.riskManager, but a caller
+	// can still set a different one directly).
+	exitATR := atr
+	if rm.Exits.ATRWindow != rm.ATRWindow {
+		exitATR = calculateATR(candles, rm.Exits.ATRWindow)
+	}
+
+	pos := openPosition{EntryPrice: entryPrice, MaxFavorable: entryPrice}
+	for i := entryIdx + 1; i < len(candles); i++ {
+		price := candles[i].Close
+		if tradeType == "long" && price > pos.MaxFavorable {
+			pos.MaxFavorable = price
+		} else if tradeType == "short" && price < pos.MaxFavorable {
+			pos.MaxFavorable = price
+		}
+
+		if exit, reason := shouldExit(pos, candles[i], exitATR[i], rm.Exits, tradeType); exit {
+			return i, price, reason
+		}
+
+		if tradeType == "long" {
+			if price <= stopPrice {
+				return i, price, "stop_loss"
+			}
+			if price >= takeProfitPrice {
+				return i, price, "take_profit"
+			}
+		} else {
+			if price >= stopPrice {
+				return i, price, "stop_loss"
+			}
+			if price <= takeProfitPrice {
+				return i, price, "take_profit"
+			}
+		}
+	}
+	return -1, 0, ""
+}
+
+// riskManager builds a RiskManager from the active User's risk knobs,
+// defaulting to a conservative 14-period ATR with no trailing tiers when
+// the user hasn't configured any.
+func (ts *TradingStrategy) riskManager() *RiskManager {
+	window := ts.user.ATRWindow
+	if window == 0 {
+		window = 14
+	}
+	slFactor := ts.user.StopLossFactor
+	if slFactor == 0 {
+		slFactor = 1.5
+	}
+	tpFactor := ts.user.TakeProfitFactor
+	if tpFactor == 0 {
+		tpFactor = 3.0
+	}
+	exits := ts.user.Exits
+	exits.TrailingActivationRatio = ts.user.TrailingActivationRatio
+	exits.TrailingCallbackRate = ts.user.TrailingCallbackRate
+	if exits.ATRWindow == 0 {
+		exits.ATRWindow = window
+	}
+	return &RiskManager{
+		ATRWindow:        window,
+		StopLossFactor:   slFactor,
+		TakeProfitFactor: tpFactor,
+		Exits:            exits,
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// TradeStats is a full backtest report computed from a strategy's closed
+// trades plus the underlying candle series. It replaces the old bare
+// total_profit_pct view with annualized risk-adjusted and drawdown metrics.
+type TradeStats struct {
+	TotalTrades       int     `json:"total_trades"`
+	WinningTrades     int     `json:"winning_trades"`
+	TotalProfitPct    float64 `json:"total_profit_pct"`
+	AvgProfitPerTrade float64 `json:"avg_profit_per_trade"`
+	// Sharpe/Sortino are the per-trade ratio scaled by sqrt(trades per year),
+	// with the annualization factor estimated from the candle series' own
+	// timestamp span (see annualizationFactor) since trades fire at
+	// irregular, data-dependent intervals rather than a fixed period.
+	Sharpe            float64 `json:"sharpe"`
+	Sortino           float64 `json:"sortino"`
+	MaxDrawdownPct    float64 `json:"max_drawdown_pct"`
+	MaxDrawdownBars   int     `json:"max_drawdown_duration_bars"`
+	ProfitFactor      float64 `json:"profit_factor"`
+	Expectancy        float64 `json:"expectancy"`
+	AvgWinPct         float64 `json:"avg_win_pct"`
+	AvgLossPct        float64 `json:"avg_loss_pct"`
+	LongestWinStreak  int     `json:"longest_winning_streak"`
+	LongestLossStreak int     `json:"longest_losing_streak"`
+}
+
+// profitPercent is the same entry/exit-to-percent conversion as
+// TradingStrategy.safeProfitPct, exposed as a free function so stats code
+// doesn't need a TradingStrategy receiver to evaluate unrealized PnL.
+func profitPercent(entryPrice, price float64, tradeType string) float64 {
+	if entryPrice == 0 {
+		return 0
+	}
+	if tradeType == "long" {
+		return ((price - entryPrice) / entryPrice) * 100
+	}
+	return ((entryPrice - price) / entryPrice) * 100
+}
+
+// annotateMFEMAE walks each trade's own open interval through candles to
+// record the best (MFE) and worst (MAE) unrealized PnL it saw while open.
+func annotateMFEMAE(trades []Trade, candles []Candle) {
+	for i := range trades {
+		entryTime, err1 := time.Parse(time.RFC3339, trades[i].EntryTime)
+		exitTime, err2 := time.Parse(time.RFC3339, trades[i].ExitTime)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		mfe, mae := math.Inf(-1), math.Inf(1)
+		for _, c := range candles {
+			if c.DateTime.Before(entryTime) || c.DateTime.After(exitTime) {
+				continue
+			}
+			pnl := profitPercent(trades[i].EntryPrice, c.Close, trades[i].TradeType)
+			if pnl > mfe {
+				mfe = pnl
+			}
+			if pnl < mae {
+				mae = pnl
+			}
+		}
+		if !math.IsInf(mfe, -1) {
+			trades[i].MFEPct = mfe
+		}
+		if !math.IsInf(mae, 1) {
+			trades[i].MAEPct = mae
+		}
+	}
+}
+
+// annualizationFactor estimates how many trades per year the series implies
+// from the candle span, so Sharpe/Sortino can be annualized despite trades
+// firing at irregular intervals instead of a fixed period. Falls back to the
+// raw trade count (the same as treating the series as spanning one year)
+// when the candle span can't be determined.
+func annualizationFactor(numTrades int, candles []Candle) float64 {
+	if numTrades == 0 {
+		return 0
+	}
+	if len(candles) < 2 {
+		return float64(numTrades)
+	}
+	years := candles[len(candles)-1].DateTime.Sub(candles[0].DateTime).Hours() / (24 * 365)
+	if years <= 0 {
+		return float64(numTrades)
+	}
+	return float64(numTrades) / years
+}
+
+// calculateTradeSummary computes the full TradeStats report for a closed set
+// of trades, annotating each trade's MFE/MAE from candles along the way.
+func calculateTradeSummary(trades []Trade, candles []Candle) TradeStats {
+	annotateMFEMAE(trades, candles)
+
+	stats := TradeStats{TotalTrades: len(trades)}
+	if len(trades) == 0 {
+		return stats
+	}
+
+	var grossWin, grossLoss, sumProfit float64
+	var winStreak, lossStreak, curWin, curLoss int
+	returns := make([]float64, len(trades))
+	for i, t := range trades {
+		returns[i] = t.ProfitPct
+		sumProfit += t.ProfitPct
+		if t.ProfitPct > 0 {
+			stats.WinningTrades++
+			grossWin += t.ProfitPct
+			curWin++
+			curLoss = 0
+		} else {
+			grossLoss += -t.ProfitPct
+			curLoss++
+			curWin = 0
+		}
+		if curWin > winStreak {
+			winStreak = curWin
+		}
+		if curLoss > lossStreak {
+			lossStreak = curLoss
+		}
+	}
+	stats.TotalProfitPct = sumProfit
+	stats.AvgProfitPerTrade = sumProfit / float64(len(trades))
+	stats.LongestWinStreak = winStreak
+	stats.LongestLossStreak = lossStreak
+
+	mean := stats.AvgProfitPerTrade
+	var variance, downsideVariance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+		if r < 0 {
+			downsideVariance += r * r
+		}
+	}
+	n := float64(len(returns))
+	stdDev := math.Sqrt(variance / n)
+	downsideDev := math.Sqrt(downsideVariance / n)
+	periodsPerYear := annualizationFactor(len(trades), candles)
+	if stdDev > 0 {
+		stats.Sharpe = (mean / stdDev) * math.Sqrt(periodsPerYear)
+	}
+	if downsideDev > 0 {
+		stats.Sortino = (mean / downsideDev) * math.Sqrt(periodsPerYear)
+	}
+
+	if grossLoss > 0 {
+		stats.ProfitFactor = grossWin / grossLoss
+	} else if grossWin > 0 {
+		stats.ProfitFactor = math.Inf(1)
+	}
+
+	lossCount := len(trades) - stats.WinningTrades
+	if stats.WinningTrades > 0 {
+		stats.AvgWinPct = grossWin / float64(stats.WinningTrades)
+	}
+	if lossCount > 0 {
+		stats.AvgLossPct = -grossLoss / float64(lossCount)
+	}
+	winRate := float64(stats.WinningTrades) / float64(len(trades))
+	stats.Expectancy = winRate*stats.AvgWinPct + (1-winRate)*stats.AvgLossPct
+
+	// Equity curve and max drawdown, walked trade-by-trade.
+	equity, peak, maxDD := 0.0, 0.0, 0.0
+	peakBar, maxDDBars := 0, 0
+	for i, t := range trades {
+		equity += t.ProfitPct
+		if equity > peak {
+			peak = equity
+			peakBar = i
+		}
+		if dd := peak - equity; dd > maxDD {
+			maxDD = dd
+			maxDDBars = i - peakBar
+		}
+	}
+	stats.MaxDrawdownPct = maxDD
+	stats.MaxDrawdownBars = maxDDBars
+
+	return stats
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	_ "modernc.org/sqlite"
+)
+
+// EquityPoint is one sample of a strategy's running PnL curve, persisted so
+// GET /equity can serve a saved run after a restart.
+type EquityPoint struct {
+	Strategy string    `json:"strategy"`
+	Symbol   string    `json:"symbol"`
+	Time     time.Time `json:"time"`
+	Equity   float64   `json:"equity"`
+}
+
+// Store persists trades, open positions, and equity points so a strategy
+// session survives a process restart. SQLiteStore and RedisStore are the two
+// implementations; NewStore picks one from the environment.
+type Store interface {
+	SaveTrade(ctx context.Context, strategy, symbol string, trade Trade) error
+	LoadTrades(ctx context.Context, strategy, symbol string, from, to time.Time) ([]Trade, error)
+	SaveOpenPosition(ctx context.Context, strategy, symbol string, pos openPosition) error
+	LoadOpenPositions(ctx context.Context, strategy, symbol string) ([]openPosition, error)
+	SaveEquityPoint(ctx context.Context, strategy, symbol string, point EquityPoint) error
+	LoadEquityPoints(ctx context.Context, strategy, symbol string) ([]EquityPoint, error)
+}
+
+// NewStore builds a Store from STORE_BACKEND ("sqlite" or "redis"; defaults
+// to "sqlite") and STORE_DSN (defaults to "trades.db" for sqlite,
+// "localhost:6379" for redis).
+func NewStore() (Store, error) {
+	switch os.Getenv("STORE_BACKEND") {
+	case "redis":
+		addr := os.Getenv("STORE_DSN")
+		if addr == "" {
+			addr = "localhost:6379"
+		}
+		return NewRedisStore(addr)
+	default:
+		path := os.Getenv("STORE_DSN")
+		if path == "" {
+			path = "trades.db"
+		}
+		return NewSQLiteStore(path)
+	}
+}
+
+// ---------------------- SQLiteStore ----------------------
+
+// SQLiteStore is the default Store backend: a single trades.db file holding
+// one row per trade/open position/equity sample.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) the SQLite database at path
+// and ensures its schema exists. Uses the pure-Go modernc.org/sqlite driver
+// rather than mattn/go-sqlite3 so the default store works on CGO_ENABLED=0
+// builds (minimal/static Docker images, cross-compiles) instead of silently
+// registering a non-functional cgo stub.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite store at %s: %v", path, err)
+	}
+	schema := `
+	CREATE TABLE IF NOT EXISTS trades (
+		strategy TEXT, symbol TEXT, entry_time TEXT, entry_price REAL,
+		exit_time TEXT, exit_price REAL, trade_type TEXT, profit_pct REAL,
+		exit_reason TEXT, pattern TEXT
+	);
+	CREATE TABLE IF NOT EXISTS open_positions (
+		strategy TEXT, symbol TEXT, entry_time TEXT, entry_price REAL,
+		stop_loss_price REAL, take_profit_price REAL
+	);
+	CREATE TABLE IF NOT EXISTS equity_points (
+		strategy TEXT, symbol TEXT, time TEXT, equity REAL
+	);
+	CREATE TABLE IF NOT EXISTS candles (
+		symbol TEXT, interval TEXT, timestamp INTEGER, open REAL, high REAL,
+		low REAL, close REAL, volume REAL
+	);`
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("initializing sqlite schema: %v", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// SaveCandles replaces the cached candle history for symbol/interval,
+// implementing backtest.go's CandleCache so Backtester can skip re-reading
+// minute_data.csv on repeat runs.
+func (s *SQLiteStore) SaveCandles(symbol, interval string, candles []Candle) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM candles WHERE symbol = ? AND interval = ?`, symbol, interval); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare(
+		`INSERT INTO candles (symbol, interval, timestamp, open, high, low, close, volume)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for _, c := range candles {
+		if _, err := stmt.Exec(symbol, interval, c.Timestamp, c.Open, c.High, c.Low, c.Close, c.Volume); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadCachedCandles returns the cached candle history for symbol/interval in
+// timestamp order, or an empty slice if nothing has been cached yet.
+func (s *SQLiteStore) LoadCachedCandles(symbol, interval string) ([]Candle, error) {
+	rows, err := s.db.Query(
+		`SELECT timestamp, open, high, low, close, volume FROM candles
+		 WHERE symbol = ? AND interval = ? ORDER BY timestamp`,
+		symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candles []Candle
+	for rows.Next() {
+		var c Candle
+		if err := rows.Scan(&c.Timestamp, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume); err != nil {
+			return nil, err
+		}
+		c.DateTime = time.UnixMilli(c.Timestamp)
+		candles = append(candles, c)
+	}
+	return candles, rows.Err()
+}
+
+func (s *SQLiteStore) SaveTrade(ctx context.Context, strategy, symbol string, trade Trade) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO trades (strategy, symbol, entry_time, entry_price, exit_time, exit_price, trade_type, profit_pct, exit_reason, pattern)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		strategy, symbol, trade.EntryTime, trade.EntryPrice, trade.ExitTime, trade.ExitPrice,
+		trade.TradeType, trade.ProfitPct, trade.ExitReason, trade.Pattern)
+	return err
+}
+
+func (s *SQLiteStore) LoadTrades(ctx context.Context, strategy, symbol string, from, to time.Time) ([]Trade, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT symbol, entry_time, entry_price, exit_time, exit_price, trade_type, profit_pct, exit_reason, pattern
+		 FROM trades WHERE strategy = ? AND symbol = ? AND entry_time >= ? AND entry_time <= ?`,
+		strategy, symbol, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trades []Trade
+	for rows.Next() {
+		var t Trade
+		if err := rows.Scan(&t.Symbol, &t.EntryTime, &t.EntryPrice, &t.ExitTime, &t.ExitPrice,
+			&t.TradeType, &t.ProfitPct, &t.ExitReason, &t.Pattern); err != nil {
+			return nil, err
+		}
+		trades = append(trades, t)
+	}
+	return trades, rows.Err()
+}
+
+func (s *SQLiteStore) SaveOpenPosition(ctx context.Context, strategy, symbol string, pos openPosition) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO open_positions (strategy, symbol, entry_time, entry_price, stop_loss_price, take_profit_price)
+		 VALUES (?, ?, ?, ?, ?, ?)`,
+		strategy, symbol, pos.EntryTime.Format(time.RFC3339), pos.EntryPrice, pos.StopLossPrice, pos.TakeProfitPrice)
+	return err
+}
+
+func (s *SQLiteStore) LoadOpenPositions(ctx context.Context, strategy, symbol string) ([]openPosition, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT entry_time, entry_price, stop_loss_price, take_profit_price
+		 FROM open_positions WHERE strategy = ? AND symbol = ?`,
+		strategy, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []openPosition
+	for rows.Next() {
+		var entryTimeStr string
+		pos := openPosition{RiskExitIdx: -1}
+		if err := rows.Scan(&entryTimeStr, &pos.EntryPrice, &pos.StopLossPrice, &pos.TakeProfitPrice); err != nil {
+			return nil, err
+		}
+		pos.EntryTime, _ = time.Parse(time.RFC3339, entryTimeStr)
+		positions = append(positions, pos)
+	}
+	return positions, rows.Err()
+}
+
+func (s *SQLiteStore) SaveEquityPoint(ctx context.Context, strategy, symbol string, point EquityPoint) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO equity_points (strategy, symbol, time, equity) VALUES (?, ?, ?, ?)`,
+		strategy, symbol, point.Time.Format(time.RFC3339), point.Equity)
+	return err
+}
+
+func (s *SQLiteStore) LoadEquityPoints(ctx context.Context, strategy, symbol string) ([]EquityPoint, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT time, equity FROM equity_points WHERE strategy = ? AND symbol = ? ORDER BY time`,
+		strategy, symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []EquityPoint
+	for rows.Next() {
+		var timeStr string
+		point := EquityPoint{Strategy: strategy, Symbol: symbol}
+		if err := rows.Scan(&timeStr, &point.Equity); err != nil {
+			return nil, err
+		}
+		point.Time, _ = time.Parse(time.RFC3339, timeStr)
+		points = append(points, point)
+	}
+	return points, rows.Err()
+}
+
+// ---------------------- RedisStore ----------------------
+
+// RedisStore keeps each strategy/symbol's trades, open positions, and
+// equity points as a JSON-encoded Redis list, for deployments that already
+// run Redis alongside the module and want a lighter-weight store than SQLite.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to the Redis instance at addr and verifies it with
+// a PING before returning.
+func NewRedisStore(addr string) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("connecting to redis at %s: %v", addr, err)
+	}
+	return &RedisStore{client: client}, nil
+}
+
+func tradeListKey(strategy, symbol string) string {
+	return fmt.Sprintf("trades:%s:%s", strategy, symbol)
+}
+func positionListKey(strategy, symbol string) string {
+	return fmt.Sprintf("positions:%s:%s", strategy, symbol)
+}
+func equityListKey(strategy, symbol string) string {
+	return fmt.Sprintf("equity:%s:%s", strategy, symbol)
+}
+
+func (s *RedisStore) SaveTrade(ctx context.Context, strategy, symbol string, trade Trade) error {
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return err
+	}
+	return s.client.RPush(ctx, tradeListKey(strategy, symbol), data).Err()
+}
+
+func (s *RedisStore) LoadTrades(ctx context.Context, strategy, symbol string, from, to time.Time) ([]Trade, error) {
+	raw, err := s.client.LRange(ctx, tradeListKey(strategy, symbol), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	var trades []Trade
+	for _, entry := range raw {
+		var t Trade
+		if err := json.Unmarshal([]byte(entry), &t); err != nil {
+			continue
+		}
+		if entryTime, err := time.Parse(time.RFC3339, t.EntryTime); err == nil {
+			if entryTime.Before(from) || entryTime.After(to) {
+				continue
+			}
+		}
+		trades = append(trades, t)
+	}
+	return trades, nil
+}
+
+func (s *RedisStore) SaveOpenPosition(ctx context.Context, strategy, symbol string, pos openPosition) error {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return err
+	}
+	return s.client.RPush(ctx, positionListKey(strategy, symbol), data).Err()
+}
+
+func (s *RedisStore) LoadOpenPositions(ctx context.Context, strategy, symbol string) ([]openPosition, error) {
+	raw, err := s.client.LRange(ctx, positionListKey(strategy, symbol), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	var positions []openPosition
+	for _, entry := range raw {
+		var pos openPosition
+		if err := json.Unmarshal([]byte(entry), &pos); err != nil {
+			continue
+		}
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+func (s *RedisStore) SaveEquityPoint(ctx context.Context, strategy, symbol string, point EquityPoint) error {
+	data, err := json.Marshal(point)
+	if err != nil {
+		return err
+	}
+	return s.client.RPush(ctx, equityListKey(strategy, symbol), data).Err()
+}
+
+func (s *RedisStore) LoadEquityPoints(ctx context.Context, strategy, symbol string) ([]EquityPoint, error) {
+	raw, err := s.client.LRange(ctx, equityListKey(strategy, symbol), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	var points []EquityPoint
+	for _, entry := range raw {
+		var point EquityPoint
+		if err := json.Unmarshal([]byte(entry), &point); err != nil {
+			continue
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+// ---------------------- TradingStrategy persistence hooks ----------------------
+
+// recordTrade saves trade to ts.store if one is configured; every
+// strategy's execute* method calls this for each trade it closes. A nil
+// store (Optimizer/backtest runs that don't want persistence) is a no-op.
+func (ts *TradingStrategy) recordTrade(symbol string, trade Trade) {
+	if ts.store == nil {
+		return
+	}
+	if err := ts.store.SaveTrade(context.Background(), ts.user.Strategy, symbol, trade); err != nil {
+		logTrade(fmt.Sprintf("store: failed to save trade for %s: %v", symbol, err))
+	}
+}
+
+// recordOpenPosition saves pos to ts.store if one is configured. Strategies
+// call this for any position still open when their evaluation window ends,
+// so a resumed session can pick the position back up via LoadOpenPositions.
+func (ts *TradingStrategy) recordOpenPosition(symbol string, pos openPosition) {
+	if ts.store == nil {
+		return
+	}
+	if err := ts.store.SaveOpenPosition(context.Background(), ts.user.Strategy, symbol, pos); err != nil {
+		logTrade(fmt.Sprintf("store: failed to save open position for %s: %v", symbol, err))
+	}
+}
+
+// recordEquityPoint saves the strategy's current cumulative profit to
+// ts.store if one is configured.
+func (ts *TradingStrategy) recordEquityPoint(symbol string, equity float64) {
+	if ts.store == nil {
+		return
+	}
+	point := EquityPoint{Strategy: ts.user.Strategy, Symbol: symbol, Time: time.Now(), Equity: equity}
+	if err := ts.store.SaveEquityPoint(context.Background(), ts.user.Strategy, symbol, point); err != nil {
+		logTrade(fmt.Sprintf("store: failed to save equity point for %s: %v", symbol, err))
+	}
+}
+
+// ---------------------- HTTP endpoints ----------------------
+
+// registerStoreEndpoints wires GET /trades, GET /positions, and GET /equity
+// as read-only views over the configured Store.
+func registerStoreEndpoints(router *gin.Engine, store Store) {
+	router.GET("/trades", func(c *gin.Context) {
+		from, to := parseRangeQuery(c)
+		trades, err := store.LoadTrades(c.Request.Context(), c.Query("strategy"), c.Query("symbol"), from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"trades": trades})
+	})
+
+	router.GET("/positions", func(c *gin.Context) {
+		positions, err := store.LoadOpenPositions(c.Request.Context(), c.Query("strategy"), c.Query("symbol"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"positions": positions})
+	})
+
+	router.GET("/equity", func(c *gin.Context) {
+		points, err := store.LoadEquityPoints(c.Request.Context(), c.Query("strategy"), c.Query("symbol"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"equity": points})
+	})
+}
+
+// parseRangeQuery reads the "from"/"to" RFC3339 query params, defaulting to
+// a wide-open range when either is absent or unparsable.
+func parseRangeQuery(c *gin.Context) (time.Time, time.Time) {
+	from, err := time.Parse(time.RFC3339, c.Query("from"))
+	if err != nil {
+		from = time.Unix(0, 0)
+	}
+	to, err := time.Parse(time.RFC3339, c.Query("to"))
+	if err != nil {
+		to = time.Now().AddDate(100, 0, 0)
+	}
+	return from, to
+}
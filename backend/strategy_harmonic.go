@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image/color"
+	"os"
+	"time"
+
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/vg"
+)
+
+func init() {
+	RegisterStrategy(legacyStrategy{id: "HARMONIC", execute: (*TradingStrategy).executeHarmonic})
+}
+
+// swingPivot is a detected swing high or low in the candle series.
+type swingPivot struct {
+	Idx   int
+	Time  time.Time
+	Price float64
+	High  bool // true for a swing high, false for a swing low
+}
+
+// findSwingPivots runs a k-bar fractal over candles: a bar is a pivot high if
+// its High is strictly greater than the k bars on each side, and symmetrically
+// for pivot lows. Consecutive same-kind pivots are collapsed to the more
+// extreme one so the result strictly alternates high/low, which is what the
+// XABCD scanner below requires.
+func findSwingPivots(candles []Candle, k int) []swingPivot {
+	var pivots []swingPivot
+	for i := k; i < len(candles)-k; i++ {
+		isHigh, isLow := true, true
+		for j := 1; j <= k; j++ {
+			if candles[i].High <= candles[i-j].High || candles[i].High <= candles[i+j].High {
+				isHigh = false
+			}
+			if candles[i].Low >= candles[i-j].Low || candles[i].Low >= candles[i+j].Low {
+				isLow = false
+			}
+		}
+		if isHigh {
+			pivots = append(pivots, swingPivot{Idx: i, Time: candles[i].DateTime, Price: candles[i].High, High: true})
+		} else if isLow {
+			pivots = append(pivots, swingPivot{Idx: i, Time: candles[i].DateTime, Price: candles[i].Low, High: false})
+		}
+	}
+
+	var alternating []swingPivot
+	for _, p := range pivots {
+		if len(alternating) == 0 {
+			alternating = append(alternating, p)
+			continue
+		}
+		last := alternating[len(alternating)-1]
+		if last.High == p.High {
+			// Same kind as the last pivot: keep whichever is more extreme.
+			if (p.High && p.Price > last.Price) || (!p.High && p.Price < last.Price) {
+				alternating[len(alternating)-1] = p
+			}
+			continue
+		}
+		alternating = append(alternating, p)
+	}
+	return alternating
+}
+
+// classifyXABCD checks the X,A,B,C,D retracement/extension ratios against the
+// Shark and Gartley templates described in executeHarmonic's request, and
+// returns the matched pattern name (or "" if none fits) along with whether it
+// is bullish (D is a swing low, so price is expected to bounce up from D).
+func classifyXABCD(x, a, b, c, d swingPivot) (pattern string, bullish bool) {
+	xa := a.Price - x.Price
+	ab := b.Price - a.Price
+	bc := c.Price - b.Price
+	cd := d.Price - c.Price
+	ad := d.Price - x.Price
+	if xa == 0 || ab == 0 || bc == 0 {
+		return "", false
+	}
+
+	abXA := abs(ab / xa)
+	bcAB := abs(bc / ab)
+	cdBC := abs(cd / bc)
+	adXA := abs(ad / xa)
+
+	const tol = 0.08
+	inRange := func(v, lo, hi float64) bool { return v >= lo-tol && v <= hi+tol }
+	near := func(v, target float64) bool { return abs(v-target) <= tol }
+
+	switch {
+	case inRange(bcAB, 1.13, 1.618) && inRange(cdBC, 1.618, 2.24) && inRange(adXA, 0.886, 1.13):
+		pattern = "Shark"
+	case near(abXA, 0.618) && inRange(bcAB, 0.382, 0.886) && inRange(cdBC, 1.13, 1.618) && near(adXA, 0.786):
+		pattern = "Gartley"
+	default:
+		return "", false
+	}
+	return pattern, !d.High
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// executeHarmonic scans the candle series for completed XABCD harmonic
+// patterns (Shark, Gartley) using pivot-based swing detection, and trades the
+// reversal at D: long with a stop just beyond D and a take-profit at the
+// 0.618 retracement of CD for a bullish pattern, mirrored for a bearish one.
+func (ts *TradingStrategy) executeHarmonic(symbol string, useCSV bool) (map[string]interface{}, error) {
+	interval := ts.user.Interval
+	if interval == "" {
+		interval = "1m"
+	}
+	candles, err := ts.fetchData(symbol, interval, 1000, useCSV)
+	if err != nil || len(candles) == 0 {
+		return map[string]interface{}{"error": fmt.Sprintf("No data fetched for %s", symbol)}, err
+	}
+
+	pivotK := ts.user.PivotK
+	if pivotK == 0 {
+		pivotK = 3
+	}
+	pivots := findSwingPivots(candles, pivotK)
+
+	var trades []Trade
+	var patternPoints []swingPivot // XABCD points for every matched pattern, for the plot overlay
+	openIdx := -1                  // candle index of an open position, -1 if flat
+
+	for i := 4; i < len(pivots); i++ {
+		x, a, b, c, d := pivots[i-4], pivots[i-3], pivots[i-2], pivots[i-1], pivots[i]
+		pattern, bullish := classifyXABCD(x, a, b, c, d)
+		if pattern == "" {
+			continue
+		}
+
+		entryIdx := d.Idx
+		if entryIdx <= openIdx {
+			continue
+		}
+		entryPrice := candles[entryIdx].Close
+		cd := d.Price - c.Price
+		takeProfit := d.Price + 0.618*(-cd) // retrace back toward C
+		var stopLoss float64
+		tradeType := "short"
+		if bullish {
+			tradeType = "long"
+			stopLoss = d.Price - abs(cd)*0.2
+			takeProfit = d.Price + 0.618*abs(cd)
+		} else {
+			stopLoss = d.Price + abs(cd)*0.2
+			takeProfit = d.Price - 0.618*abs(cd)
+		}
+
+		exitIdx := len(candles) - 1
+		exitReason := "end_of_data"
+		for j := entryIdx + 1; j < len(candles); j++ {
+			price := candles[j].Close
+			if (bullish && price <= stopLoss) || (!bullish && price >= stopLoss) {
+				exitIdx, exitReason = j, "stop_loss"
+				break
+			}
+			if (bullish && price >= takeProfit) || (!bullish && price <= takeProfit) {
+				exitIdx, exitReason = j, "take_profit"
+				break
+			}
+		}
+		exitPrice := candles[exitIdx].Close
+		profit := ts.safeProfitPct(entryPrice, exitPrice, tradeType)
+
+		trades = append(trades, Trade{
+			Symbol:          symbol,
+			EntryTime:       candles[entryIdx].DateTime.Format(time.RFC3339),
+			EntryPrice:      entryPrice,
+			ExitTime:        candles[exitIdx].DateTime.Format(time.RFC3339),
+			ExitPrice:       exitPrice,
+			TradeType:       tradeType,
+			ProfitPct:       profit,
+			ExitReason:      exitReason,
+			StopLossPrice:   stopLoss,
+			TakeProfitPrice: takeProfit,
+			Pattern:         pattern,
+		})
+		logTrade(fmt.Sprintf("Harmonic %s trade for %s: %s pattern closed at %s (price: %.4f) | P/L: %.2f%%",
+			tradeType, symbol, pattern, candles[exitIdx].DateTime.Format(time.RFC3339), exitPrice, profit))
+
+		patternPoints = append(patternPoints, x, a, b, c, d)
+		openIdx = exitIdx
+	}
+
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generateHarmonicPlot(candles, trades, patternPoints, ts.user.TradeType, ts.user.UseHeikinAshi)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"trades":  trades,
+		"plot":    plotImage,
+		"summary": summary,
+	}, nil
+}
+
+// generateHarmonicPlot draws the price series, trade markers, and the XABCD
+// polyline for every matched pattern, returning a base64-encoded PNG.
+func generateHarmonicPlot(candles []Candle, trades []Trade, patternPoints []swingPivot, tradeType string, useHeikinAshi bool) (string, error) {
+	p := plot.New()
+	p.Title.Text = fmt.Sprintf("HARMONIC Strategy - %s Trades%s", tradeType, candleModeLabel(useHeikinAshi))
+	p.X.Label.Text = "Timestamp"
+	p.Y.Label.Text = "Price"
+
+	pts := make(plotter.XYs, len(candles))
+	for i, c := range candles {
+		pts[i].X = float64(c.Timestamp)
+		pts[i].Y = c.Close
+	}
+	line, err := plotter.NewLine(pts)
+	if err != nil {
+		return "", err
+	}
+	p.Add(line)
+
+	for _, trade := range trades {
+		entryTime, _ := time.Parse(time.RFC3339, trade.EntryTime)
+		exitTime, _ := time.Parse(time.RFC3339, trade.ExitTime)
+		entryX := float64(entryTime.UnixMilli())
+		exitX := float64(exitTime.UnixMilli())
+
+		tradeLine, err := plotter.NewLine(plotter.XYs{
+			{X: entryX, Y: trade.EntryPrice},
+			{X: exitX, Y: trade.ExitPrice},
+		})
+		if err == nil {
+			tradeLine.Dashes = []vg.Length{vg.Points(5), vg.Points(5)}
+			p.Add(tradeLine)
+		}
+	}
+
+	// Draw every matched pattern's X-A-B-C-D polyline in a distinct color.
+	for i := 0; i+5 <= len(patternPoints); i += 5 {
+		xabcd := patternPoints[i : i+5]
+		polyPts := make(plotter.XYs, len(xabcd))
+		for j, pv := range xabcd {
+			polyPts[j].X = float64(pv.Time.UnixMilli())
+			polyPts[j].Y = pv.Price
+		}
+		polyLine, err := plotter.NewLine(polyPts)
+		if err != nil {
+			continue
+		}
+		polyLine.Color = color.RGBA{B: 200, A: 255}
+		p.Add(polyLine)
+	}
+
+	tmpFile, err := os.CreateTemp("", "harmonic-plot-*.png")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpFile.Name())
+	if err := p.Save(500, 300, tmpFile.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func init() {
+	RegisterStrategy(legacyStrategy{id: "IRR", execute: (*TradingStrategy).executeIRR})
+}
+
+// simpleMovingAverage returns the trailing mean of series over period bars,
+// leaving the first period-1 entries at zero (not enough history yet).
+func simpleMovingAverage(series []float64, period int) []float64 {
+	sma := make([]float64, len(series))
+	if period <= 0 {
+		return sma
+	}
+	sum := 0.0
+	for i, v := range series {
+		sum += v
+		if i >= period {
+			sum -= series[i-period]
+		}
+		if i >= period-1 {
+			sma[i] = sum / float64(period)
+		}
+	}
+	return sma
+}
+
+// executeIRR is a mean-reversion strategy: the alpha is the sign-flipped
+// one-period return (close-open)/open, so a strongly down candle becomes a
+// bullish signal. A fast/slow SMA cross of that same alpha series acts as a
+// regime filter so longs only fire while the smoothed alpha is trending up.
+// Positions exit on the next opposite signal, a max holding period, or the
+// shared ATR-based RiskManager, whichever comes first.
+func (ts *TradingStrategy) executeIRR(symbol string, useCSV bool) (map[string]interface{}, error) {
+	interval := ts.user.Interval
+	if interval == "" {
+		interval = "1m"
+	}
+	candles, err := ts.fetchData(symbol, interval, 1000, useCSV)
+	if err != nil || len(candles) == 0 {
+		return map[string]interface{}{"error": fmt.Sprintf("No data fetched for %s", symbol)}, err
+	}
+
+	fastPeriod := ts.user.FastMA
+	if fastPeriod == 0 {
+		fastPeriod = 5
+	}
+	slowPeriod := ts.user.SlowMA
+	if slowPeriod == 0 {
+		slowPeriod = 20
+	}
+	maxHoldBars := ts.user.Window
+	if maxHoldBars == 0 {
+		maxHoldBars = 20
+	}
+	threshold := ts.user.Threshold
+	if threshold == 0 {
+		threshold = 0.002
+	}
+	if len(candles) <= slowPeriod {
+		return map[string]interface{}{"error": fmt.Sprintf("Not enough candles for %s", symbol)}, nil
+	}
+
+	alpha := make([]float64, len(candles))
+	for i, c := range candles {
+		if c.Open == 0 {
+			continue
+		}
+		alpha[i] = -(c.Close - c.Open) / c.Open
+	}
+	smaFast := simpleMovingAverage(alpha, fastPeriod)
+	smaSlow := simpleMovingAverage(alpha, slowPeriod)
+
+	rm := ts.riskManager()
+	atr := calculateATR(candles, rm.ATRWindow)
+	var trades []Trade
+	var openPositions []openPosition
+	var entryBar []int // bar index each open position entered, for maxHoldBars
+
+	for i := slowPeriod; i < len(candles); i++ {
+		currentTime := candles[i].DateTime
+		currentPrice := candles[i].Close
+
+		if len(openPositions) > 0 {
+			pos := openPositions[0]
+			heldBars := i - entryBar[0]
+			oppositeSignal := (ts.user.TradeType == "long" && alpha[i] < -threshold) ||
+				(ts.user.TradeType == "short" && alpha[i] > threshold)
+			riskHit := pos.RiskExitIdx >= 0 && i >= pos.RiskExitIdx
+			maxHoldHit := heldBars >= maxHoldBars
+
+			if riskHit || oppositeSignal || maxHoldHit {
+				openPositions = openPositions[1:]
+				entryBar = entryBar[1:]
+
+				exitPrice, exitTime, reason := currentPrice, currentTime, "signal"
+				if maxHoldHit && !oppositeSignal && !riskHit {
+					reason = "max_hold"
+				}
+				if riskHit {
+					exitPrice = candles[pos.RiskExitIdx].Close
+					exitTime = candles[pos.RiskExitIdx].DateTime
+					reason = pos.RiskExitReason
+				}
+
+				profit := ts.safeProfitPct(pos.EntryPrice, exitPrice, ts.user.TradeType)
+				trade := Trade{
+					Symbol:          symbol,
+					EntryTime:       pos.EntryTime.Format(time.RFC3339),
+					EntryPrice:      pos.EntryPrice,
+					ExitTime:        exitTime.Format(time.RFC3339),
+					ExitPrice:       exitPrice,
+					TradeType:       ts.user.TradeType,
+					ProfitPct:       profit,
+					ExitReason:      reason,
+					StopLossPrice:   pos.StopLossPrice,
+					TakeProfitPrice: pos.TakeProfitPrice,
+				}
+				trades = append(trades, trade)
+				logTrade(fmt.Sprintf("IRR %s trade for %s: closed by %s at %s (price: %.4f) | P/L: %.2f%%",
+					ts.user.TradeType, symbol, reason, exitTime.Format(time.RFC3339), exitPrice, profit))
+				continue
+			}
+		}
+
+		if len(openPositions) == 0 {
+			regimeBullish := smaFast[i] > smaSlow[i]
+			if ts.user.TradeType == "long" && alpha[i] > threshold && regimeBullish {
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "long")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice - rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice + rm.TakeProfitFactor*atr[i],
+				})
+				entryBar = append(entryBar, i)
+			} else if ts.user.TradeType == "short" && alpha[i] < -threshold && !regimeBullish {
+				riskExitIdx, _, riskReason := rm.ApplyExits(candles, i, currentPrice, "short")
+				openPositions = append(openPositions, openPosition{
+					EntryTime: currentTime, EntryPrice: currentPrice,
+					RiskExitIdx: riskExitIdx, RiskExitReason: riskReason,
+					StopLossPrice:   currentPrice + rm.StopLossFactor*atr[i],
+					TakeProfitPrice: currentPrice - rm.TakeProfitFactor*atr[i],
+				})
+				entryBar = append(entryBar, i)
+			}
+		}
+	}
+
+	for _, t := range trades {
+		ts.recordTrade(symbol, t)
+	}
+	for _, pos := range openPositions {
+		ts.recordOpenPosition(symbol, pos)
+	}
+	summary := calculateTradeSummary(trades, candles)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	plotImage, err := generatePlots(candles, trades, "IRR", ts.user.RSIPeriod, ts.user.MAPeriod, ts.user.TradeType, ts.user.UseHeikinAshi)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"trades":  trades,
+		"plot":    plotImage,
+		"summary": summary,
+	}, nil
+}
@@ -0,0 +1,415 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	RegisterStrategy(legacyStrategy{id: "RAMSEY", execute: (*TradingStrategy).executeRamsey})
+}
+
+// RamseyResult is the systemic-risk signal computed from a basket's Pearson
+// correlation matrix: the detected maximum clique (assets that move
+// together, a risk-off cluster) and maximum independent set (assets that
+// don't, a diversification opportunity), labeled by symbol rather than
+// matrix index.
+type RamseyResult struct {
+	Symbols           []string    `json:"symbols"`
+	Matrix            [][]float64 `json:"correlation_matrix"`
+	Signal            string      `json:"signal"`
+	MaxClique         []string    `json:"max_clique"`
+	MaxIndependentSet []string    `json:"max_independent_set"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// ramseyCache holds the most recently computed RamseyResult so GET
+// /ramsey/matrix can serve it without recomputing on every request, and so
+// the rolling recompute goroutine (see executeRamsey) has somewhere to
+// publish updates. rolling guards against starting more than one roller in
+// the process: once one is running, a later call does not start another,
+// even for a different basket.
+type ramseyCache struct {
+	mu      sync.RWMutex
+	result  *RamseyResult
+	rolling bool
+}
+
+var globalRamseyCache = &ramseyCache{}
+
+func (c *ramseyCache) get() (RamseyResult, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.result == nil {
+		return RamseyResult{}, false
+	}
+	return *c.result, true
+}
+
+func (c *ramseyCache) set(result RamseyResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.result = &result
+}
+
+// startRollingIfNeeded spawns a goroutine that recomputes the basket's
+// RamseyResult every cadence and publishes it to the cache, unless one is
+// already running. It never stops itself; this mirrors the rest of the
+// module's assumption of a single long-lived server process.
+func (c *ramseyCache) startRollingIfNeeded(ts *TradingStrategy, symbols []string, interval string, window int, threshold float64, targetClique, targetIndependent int, useCSV bool, cadence time.Duration) {
+	c.mu.Lock()
+	if c.rolling {
+		c.mu.Unlock()
+		return
+	}
+	c.rolling = true
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(cadence)
+		defer ticker.Stop()
+		for range ticker.C {
+			result, err := computeRamseyResult(ts, symbols, interval, window, threshold, targetClique, targetIndependent, useCSV)
+			if err != nil {
+				logTrade(fmt.Sprintf("RAMSEY roller: %v", err))
+				continue
+			}
+			globalRamseyCache.set(result)
+		}
+	}()
+}
+
+// pearsonCorrelation computes the Pearson correlation coefficient between
+// two equal-length return series, returning 0 for a degenerate (zero
+// variance) series.
+func pearsonCorrelation(a, b []float64) float64 {
+	n := len(a)
+	if n == 0 || n != len(b) {
+		return 0
+	}
+	var sumA, sumB float64
+	for i := 0; i < n; i++ {
+		sumA += a[i]
+		sumB += b[i]
+	}
+	meanA, meanB := sumA/float64(n), sumB/float64(n)
+	var cov, varA, varB float64
+	for i := 0; i < n; i++ {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varA*varB)
+}
+
+// fetchAlignedReturns concurrently fetches window candles per symbol at
+// interval, converts each into a close-price log-return series keyed by
+// candle open time, and intersects the timestamps across every symbol that
+// fetched successfully so every series lines up on the same grid. Symbols
+// that fail to fetch or have too little history are dropped.
+func (ts *TradingStrategy) fetchAlignedReturns(symbols []string, interval string, window int, useCSV bool) (map[string][]float64, []string, error) {
+	type fetchResult struct {
+		symbol  string
+		candles []Candle
+		err     error
+	}
+	results := make(chan fetchResult, len(symbols))
+	var wg sync.WaitGroup
+	for _, sym := range symbols {
+		wg.Add(1)
+		go func(sym string) {
+			defer wg.Done()
+			candles, err := ts.fetchData(sym, interval, window, useCSV)
+			results <- fetchResult{symbol: sym, candles: candles, err: err}
+		}(sym)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	returnsBySymbol := make(map[string]map[int64]float64)
+	var validSymbols []string
+	for r := range results {
+		if r.err != nil || len(r.candles) < 2 {
+			logTrade(fmt.Sprintf("RAMSEY: skipping %s (fetch error or too little history): %v", r.symbol, r.err))
+			continue
+		}
+		byTime := make(map[int64]float64, len(r.candles)-1)
+		for i := 1; i < len(r.candles); i++ {
+			prevClose, closePrice := r.candles[i-1].Close, r.candles[i].Close
+			if prevClose == 0 {
+				continue
+			}
+			byTime[r.candles[i].Timestamp] = math.Log(closePrice / prevClose)
+		}
+		returnsBySymbol[r.symbol] = byTime
+		validSymbols = append(validSymbols, r.symbol)
+	}
+	sort.Strings(validSymbols)
+	if len(validSymbols) == 0 {
+		return nil, nil, fmt.Errorf("no symbols returned usable candle data")
+	}
+
+	var commonTimes []int64
+	for t := range returnsBySymbol[validSymbols[0]] {
+		inAll := true
+		for _, sym := range validSymbols[1:] {
+			if _, ok := returnsBySymbol[sym][t]; !ok {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			commonTimes = append(commonTimes, t)
+		}
+	}
+	sort.Slice(commonTimes, func(i, j int) bool { return commonTimes[i] < commonTimes[j] })
+
+	aligned := make(map[string][]float64, len(validSymbols))
+	for _, sym := range validSymbols {
+		series := make([]float64, len(commonTimes))
+		for i, t := range commonTimes {
+			series[i] = returnsBySymbol[sym][t]
+		}
+		aligned[sym] = series
+	}
+	return aligned, validSymbols, nil
+}
+
+// bronKerbosch finds every maximal clique in adj (N vertices) via the
+// standard recursive algorithm (no pivoting), keeping the largest seen.
+func bronKerbosch(n int, adj [][]bool) []int {
+	var maxClique []int
+	var recurse func(r, p, x []int)
+	recurse = func(r, p, x []int) {
+		if len(p) == 0 && len(x) == 0 {
+			if len(r) > len(maxClique) {
+				cp := make([]int, len(r))
+				copy(cp, r)
+				maxClique = cp
+			}
+			return
+		}
+		for i := 0; i < len(p); i++ {
+			v := p[i]
+			var nv []int
+			for j := 0; j < n; j++ {
+				if adj[v][j] {
+					nv = append(nv, j)
+				}
+			}
+			var pNew, xNew []int
+			for _, w := range p {
+				for _, u := range nv {
+					if w == u {
+						pNew = append(pNew, w)
+						break
+					}
+				}
+			}
+			for _, w := range x {
+				for _, u := range nv {
+					if w == u {
+						xNew = append(xNew, w)
+						break
+					}
+				}
+			}
+			recurse(append(r, v), pNew, xNew)
+			p = append(p[:i], p[i+1:]...)
+			x = append(x, v)
+			i--
+		}
+	}
+	all := make([]int, n)
+	for i := range all {
+		all[i] = i
+	}
+	recurse(nil, all, nil)
+	return maxClique
+}
+
+// computeRamseyResult runs the full pipeline: fetch+align returns for
+// symbols, build the Pearson correlation matrix, then run Bron-Kerbosch over
+// the correlation-threshold graph (clique) and its complement (independent
+// set) exactly as the original random-matrix version did, translating
+// indices back to symbol labels for the result.
+func computeRamseyResult(ts *TradingStrategy, symbols []string, interval string, window int, threshold float64, targetClique, targetIndependent int, useCSV bool) (RamseyResult, error) {
+	aligned, validSymbols, err := ts.fetchAlignedReturns(symbols, interval, window, useCSV)
+	if err != nil {
+		return RamseyResult{}, err
+	}
+	n := len(validSymbols)
+
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		matrix[i][i] = 1.0
+		for j := i + 1; j < n; j++ {
+			corr := pearsonCorrelation(aligned[validSymbols[i]], aligned[validSymbols[j]])
+			matrix[i][j] = corr
+			matrix[j][i] = corr
+		}
+	}
+
+	adj := make([][]bool, n)
+	adjComplement := make([][]bool, n)
+	for i := 0; i < n; i++ {
+		adj[i] = make([]bool, n)
+		adjComplement[i] = make([]bool, n)
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			adj[i][j] = matrix[i][j] >= threshold
+			adjComplement[i][j] = !adj[i][j]
+		}
+	}
+
+	maxClique := bronKerbosch(n, adj)
+	maxIndependent := bronKerbosch(n, adjComplement)
+
+	signal := "Neutral"
+	if len(maxClique) >= targetClique {
+		signal = "High Systemic Risk (risk-off)"
+	} else if len(maxIndependent) >= targetIndependent {
+		signal = "Arbitrage/Diversification Opportunity (risk-on)"
+	}
+
+	cliqueSymbols := make([]string, len(maxClique))
+	for i, idx := range maxClique {
+		cliqueSymbols[i] = validSymbols[idx]
+	}
+	independentSymbols := make([]string, len(maxIndependent))
+	for i, idx := range maxIndependent {
+		independentSymbols[i] = validSymbols[idx]
+	}
+
+	return RamseyResult{
+		Symbols:           validSymbols,
+		Matrix:            matrix,
+		Signal:            signal,
+		MaxClique:         cliqueSymbols,
+		MaxIndependentSet: independentSymbols,
+		UpdatedAt:         time.Now(),
+	}, nil
+}
+
+// defaultRamseyBasket is used when a caller doesn't configure RamseySymbols,
+// so RAMSEY still has a sensible multi-asset basket to analyze.
+var defaultRamseyBasket = []string{
+	"BTCUSDT", "ETHUSDT", "BNBUSDT", "SOLUSDT", "XRPUSDT",
+	"ADAUSDT", "DOGEUSDT", "AVAXUSDT", "DOTUSDT", "LINKUSDT",
+}
+
+// executeRamsey computes the real systemic-risk signal for ts.user's symbol
+// basket: a Pearson correlation matrix over aligned 1m (or User.Interval)
+// returns, then the Bron-Kerbosch maximum clique/independent-set analysis
+// the original implementation already used. If ts.user.RamseyCadenceSeconds
+// is set, it also starts a background roller that keeps globalRamseyCache
+// fresh so GET /ramsey/matrix reflects a continuously updating signal.
+func (ts *TradingStrategy) executeRamsey(symbol string, useCSV bool) (map[string]interface{}, error) {
+	symbols := ts.user.RamseySymbols
+	if len(symbols) == 0 {
+		symbols = defaultRamseyBasket
+	}
+	interval := ts.user.Interval
+	if interval == "" {
+		interval = "1m"
+	}
+	window := ts.user.RamseyWindow
+	if window == 0 {
+		window = 500
+	}
+	threshold := ts.user.RamseyCorrelationThreshold
+	if threshold == 0 {
+		threshold = 0.7
+	}
+	targetClique := ts.user.RamseyTargetCliqueSize
+	if targetClique == 0 {
+		targetClique = 4
+	}
+	targetIndependent := ts.user.RamseyTargetIndependentSize
+	if targetIndependent == 0 {
+		targetIndependent = 4
+	}
+
+	result, err := computeRamseyResult(ts, symbols, interval, window, threshold, targetClique, targetIndependent, useCSV)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}, nil
+	}
+	globalRamseyCache.set(result)
+
+	if ts.user.RamseyCadenceSeconds > 0 {
+		cadence := time.Duration(ts.user.RamseyCadenceSeconds) * time.Second
+		globalRamseyCache.startRollingIfNeeded(ts, symbols, interval, window, threshold, targetClique, targetIndependent, useCSV, cadence)
+	}
+
+	return map[string]interface{}{
+		"signal":               result.Signal,
+		"max_clique_size":      len(result.MaxClique),
+		"max_independent_size": len(result.MaxIndependentSet),
+		"max_clique":           result.MaxClique,
+		"max_independent_set":  result.MaxIndependentSet,
+		"symbols":              result.Symbols,
+		"correlation_matrix":   result.Matrix,
+	}, nil
+}
+
+// registerRamseyEndpoint wires GET /ramsey/matrix: returns the cached
+// RamseyResult if one has been computed yet (by a prior /trade RAMSEY run or
+// a running roller), otherwise computes one on demand from query params.
+func registerRamseyEndpoint(router *gin.Engine, sessions *SessionManager) {
+	router.GET("/ramsey/matrix", func(c *gin.Context) {
+		if cached, ok := globalRamseyCache.get(); ok && c.Query("refresh") == "" {
+			c.JSON(200, cached)
+			return
+		}
+
+		exchangeID := c.DefaultQuery("exchange", "binance")
+		exchange, ok := sessions.Get(exchangeID)
+		if !ok {
+			c.JSON(400, gin.H{"error": fmt.Sprintf("Unknown exchange %q. Available: %v", exchangeID, sessions.Names())})
+			return
+		}
+		symbols := defaultRamseyBasket
+		if raw := c.Query("symbols"); raw != "" {
+			symbols = nil
+			for _, sym := range strings.Split(raw, ",") {
+				if sym = strings.TrimSpace(sym); sym != "" {
+					symbols = append(symbols, sym)
+				}
+			}
+		}
+		window := 500
+		if raw := c.Query("window"); raw != "" {
+			fmt.Sscanf(raw, "%d", &window)
+		}
+		threshold := 0.7
+		if raw := c.Query("threshold"); raw != "" {
+			fmt.Sscanf(raw, "%f", &threshold)
+		}
+
+		ts := &TradingStrategy{user: User{TradeType: "long"}, exchange: exchange}
+		result, err := computeRamseyResult(ts, symbols, c.DefaultQuery("interval", "1m"), window, threshold, 4, 4, c.Query("use_csv") == "true")
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		globalRamseyCache.set(result)
+		c.JSON(200, result)
+	})
+}
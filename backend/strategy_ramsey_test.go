@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestBronKerboschMaxClique(t *testing.T) {
+	cases := []struct {
+		name string
+		n    int
+		adj  [][]bool
+		want int
+	}{
+		{
+			name: "triangle is a 3-clique",
+			n:    3,
+			adj: [][]bool{
+				{false, true, true},
+				{true, false, true},
+				{true, true, false},
+			},
+			want: 3,
+		},
+		{
+			name: "no edges has only singleton cliques",
+			n:    4,
+			adj: [][]bool{
+				{false, false, false, false},
+				{false, false, false, false},
+				{false, false, false, false},
+				{false, false, false, false},
+			},
+			want: 1,
+		},
+		{
+			name: "square (4-cycle) has max clique 2",
+			n:    4,
+			adj: [][]bool{
+				{false, true, false, true},
+				{true, false, true, false},
+				{false, true, false, true},
+				{true, false, true, false},
+			},
+			want: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clique := bronKerbosch(tc.n, tc.adj)
+			if len(clique) != tc.want {
+				t.Errorf("bronKerbosch(%d, ...) = %v (len %d), want len %d", tc.n, clique, len(clique), tc.want)
+			}
+		})
+	}
+}
+
+func TestPearsonCorrelation(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{name: "identical series", a: []float64{1, 2, 3, 4}, b: []float64{1, 2, 3, 4}, want: 1},
+		{name: "inverted series", a: []float64{1, 2, 3, 4}, b: []float64{4, 3, 2, 1}, want: -1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := pearsonCorrelation(tc.a, tc.b)
+			if diff := got - tc.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("pearsonCorrelation(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+func init() {
+	RegisterStrategy(legacyStrategy{id: "TRI", execute: (*TradingStrategy).executeTriangular})
+}
+
+// triangularRatios computes the forward and reverse round-trip ratios for
+// one cycle, after deducting netFeeFactor's three taker fees. A ratio above
+// 1 means that walking the cycle in that direction returns more than was
+// risked:
+//
+//   - forward: start with 1 unit of the quote currency, buy leg1 and leg2 at
+//     the ask to walk it around the triangle, then sell leg3 at the bid to
+//     land back in the quote currency: leg3.Bid / (leg1.Ask * leg2.Ask)
+//   - reverse: the same cycle walked the other way around the triangle,
+//     which swaps which side of the book each leg hits:
+//     (leg1.Bid * leg2.Bid) / leg3.Ask
+func triangularRatios(bid1, ask1, bid2, ask2, bid3, ask3, netFeeFactor float64) (forward, reverse float64) {
+	forward = bid3 / (ask1 * ask2) * netFeeFactor
+	reverse = (bid1 * bid2) / ask3 * netFeeFactor
+	return forward, reverse
+}
+
+// executeTriangular scans each configured TriPath for a cross-market
+// arbitrage cycle using triangularRatios. Whenever either ratio clears
+// MinSpreadRatio, a simulated three-leg Trade is recorded with the
+// synthesized profit and which direction won. This requires live order book
+// data rather than 1m klines, so it only runs against an Exchange that
+// implements FetchOrderBook meaningfully (see exchange.go).
+func (ts *TradingStrategy) executeTriangular(symbol string, useCSV bool) (map[string]interface{}, error) {
+	if ts.exchange == nil {
+		return map[string]interface{}{"error": "TRI strategy requires an exchange adapter"}, nil
+	}
+	paths := ts.user.TriPaths
+	if len(paths) == 0 {
+		return map[string]interface{}{"error": "No triangular arbitrage paths configured"}, nil
+	}
+	minSpread := ts.user.MinSpreadRatio
+	if minSpread == 0 {
+		minSpread = 1.0011
+	}
+	feeRate := ts.user.TakerFeeRate
+	if feeRate == 0 {
+		feeRate = 0.001
+	}
+	feeFactor := 1 - feeRate
+	netFeeFactor := feeFactor * feeFactor * feeFactor
+
+	var trades []Trade
+	for _, path := range paths {
+		cycle := fmt.Sprintf("%s/%s/%s", path.Leg1, path.Leg2, path.Leg3)
+		start := time.Now()
+		bid1, ask1, err1 := ts.exchange.FetchOrderBook(path.Leg1)
+		bid2, ask2, err2 := ts.exchange.FetchOrderBook(path.Leg2)
+		bid3, ask3, err3 := ts.exchange.FetchOrderBook(path.Leg3)
+		latency := time.Since(start)
+		if err1 != nil || err2 != nil || err3 != nil {
+			logTrade(fmt.Sprintf("TRI cycle %s: order book fetch failed (latency %s): %v %v %v", cycle, latency, err1, err2, err3))
+			continue
+		}
+		if ask1 == 0 || ask2 == 0 || bid1 == 0 || bid2 == 0 || ask3 == 0 {
+			continue
+		}
+
+		forward, reverse := triangularRatios(bid1, ask1, bid2, ask2, bid3, ask3, netFeeFactor)
+
+		direction, ratio := "", 0.0
+		switch {
+		case forward >= minSpread && forward >= reverse:
+			direction, ratio = "forward", forward
+		case reverse >= minSpread:
+			direction, ratio = "reverse", reverse
+		default:
+			logTrade(fmt.Sprintf("TRI cycle %s: no spread (forward=%.5f reverse=%.5f, latency %s)", cycle, forward, reverse, latency))
+			continue
+		}
+
+		profitPct := (ratio - 1) * 100
+		entryPrice, exitPrice := ask1, bid3
+		if direction == "reverse" {
+			entryPrice, exitPrice = bid1, ask3
+		}
+		now := time.Now()
+		trade := Trade{
+			Symbol:     cycle,
+			EntryTime:  start.Format(time.RFC3339),
+			EntryPrice: entryPrice,
+			ExitTime:   now.Format(time.RFC3339),
+			ExitPrice:  exitPrice,
+			TradeType:  direction,
+			ProfitPct:  profitPct,
+			ExitReason: "triangular_arbitrage",
+			Pattern:    direction,
+		}
+		trades = append(trades, trade)
+		ts.recordTrade(cycle, trade)
+		logTrade(fmt.Sprintf("TRI cycle %s: %s spread cleared (ratio=%.5f, profit=%.4f%%, latency %s)",
+			cycle, direction, ratio, profitPct, latency))
+	}
+
+	summary := calculateTradeSummary(trades, nil)
+	ts.recordEquityPoint(symbol, summary.TotalProfitPct)
+	return map[string]interface{}{
+		"trades":  trades,
+		"plot":    "",
+		"summary": summary,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import "testing"
+
+// TestTriangularRatiosDirection guards against the reciprocal-formula bug
+// where a genuine forward arbitrage opportunity evaluated as unprofitable
+// and a genuine loss in the reverse direction evaluated as a profitable
+// ~1.89% trade.
+func TestTriangularRatiosDirection(t *testing.T) {
+	const netFeeFactor = 1.0 // isolate the ratio direction from fee sizing
+
+	t.Run("genuine forward arbitrage scores above 1", func(t *testing.T) {
+		// USDT -> BTC (ask1) -> ETH (ask2) -> USDT (bid3).
+		forward, _ := triangularRatios(49995, 50000, 0.0599, 0.06, 3050, 3055, netFeeFactor)
+		if forward <= 1 {
+			t.Errorf("forward = %v, want > 1 for a genuine arbitrage opportunity", forward)
+		}
+	})
+
+	t.Run("genuine reverse loss scores below 1", func(t *testing.T) {
+		// The same quotes walked the other way are a real loss, not a profit.
+		_, reverse := triangularRatios(49995, 50000, 0.0599, 0.06, 3050, 3055, netFeeFactor)
+		if reverse >= 1 {
+			t.Errorf("reverse = %v, want < 1 for a genuine loss", reverse)
+		}
+	})
+
+	t.Run("quotes that looked like a fabricated reverse profit under the old formula score below 1", func(t *testing.T) {
+		bid1, ask1 := 49990.0, 50010.0
+		bid2, ask2 := 0.0599, 0.0601
+		bid3, ask3 := 3049.0, 3051.0
+		_, reverse := triangularRatios(bid1, ask1, bid2, ask2, bid3, ask3, netFeeFactor)
+		if reverse >= 1 {
+			t.Errorf("reverse = %v, want < 1 (old reciprocal formula reported ~1.0189 here)", reverse)
+		}
+	})
+}
+
+// fakeOrderBookExchange is a minimal Exchange stub that serves canned
+// bid/ask quotes for executeTriangular's end-to-end test.
+type fakeOrderBookExchange struct {
+	quotes map[string][2]float64 // symbol -> [bid, ask]
+}
+
+func (f *fakeOrderBookExchange) Name() string { return "fake" }
+func (f *fakeOrderBookExchange) FetchKlines(symbol, interval string, limit int) ([]Candle, error) {
+	return nil, nil
+}
+func (f *fakeOrderBookExchange) ExchangeInfo() ([]string, error) { return nil, nil }
+func (f *fakeOrderBookExchange) FetchOrderBook(symbol string) (bid, ask float64, err error) {
+	q := f.quotes[symbol]
+	return q[0], q[1], nil
+}
+
+func TestExecuteTriangularTradesTheProfitableDirection(t *testing.T) {
+	exchange := &fakeOrderBookExchange{quotes: map[string][2]float64{
+		"BTCUSDT": {49995, 50000},
+		"ETHBTC":  {0.0599, 0.06},
+		"ETHUSDT": {3050, 3055},
+	}}
+	ts := &TradingStrategy{
+		user: User{
+			TriPaths:       []TriPath{{Leg1: "BTCUSDT", Leg2: "ETHBTC", Leg3: "ETHUSDT"}},
+			MinSpreadRatio: 1.0011,
+			TakerFeeRate:   0.001,
+		},
+		exchange: exchange,
+	}
+
+	result, err := ts.executeTriangular("ignored", false)
+	if err != nil {
+		t.Fatalf("executeTriangular returned error: %v", err)
+	}
+	trades, ok := result["trades"].([]Trade)
+	if !ok || len(trades) != 1 {
+		t.Fatalf("result[\"trades\"] = %v, want exactly one trade", result["trades"])
+	}
+	if trades[0].TradeType != "forward" {
+		t.Errorf("trade direction = %q, want %q", trades[0].TradeType, "forward")
+	}
+	if trades[0].ProfitPct <= 0 {
+		t.Errorf("ProfitPct = %v, want > 0 for a genuine forward arbitrage", trades[0].ProfitPct)
+	}
+}